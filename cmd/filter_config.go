@@ -0,0 +1,18 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+/*
+initFilterFlags sets the flags driving filterRepos, shared by every command that selects repos
+
+	'thisCmd' command to be given the flags
+*/
+func initFilterFlags(thisCmd *cobra.Command) {
+	thisCmd.Flags().BoolVar(&config.dirtyOnly, "dirty-only", false, "only repos with changes to tracked files")
+	thisCmd.Flags().BoolVar(&config.aheadOnly, "ahead", false, "only repos with commits to push")
+	thisCmd.Flags().BoolVar(&config.behindOnly, "behind", false, "only repos with commits to merge")
+	thisCmd.Flags().BoolVar(&config.divergedOnly, "diverged", false, "only repos both ahead and behind upstream")
+	thisCmd.Flags().BoolVar(&config.noUpstreamOnly, "no-upstream", false, "only repos which branch has no upstream")
+	thisCmd.Flags().StringVar(&config.onBranch, "on-branch", "", "only repos with `BRANCH` checked out")
+	thisCmd.Flags().StringArrayVar(&config.where, "where", []string{}, "only repos matching `expression` on json field, e.g. 'stashCount>0', repeatable")
+}
@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type tCondition struct {
+	field  string // Name of tRepo's json field
+	index  int    // Index of tRepo's field
+	op     string // Empty when bool field is tested alone
+	value  string
+	negate bool           // Set by `!` prefix
+	regex  *regexp.Regexp // Compiled value of `~` operator
+}
+
+/*
+Operators of --where, two-char ones first
+*/
+var whereOperators = []string{"==", "!=", "<=", ">=", "~", "<", ">"}
+
+/*
+Fields retrieved only when their column is shown, by json name
+*/
+var whereColumns = map[string]string{
+	"originUrl":         "url",
+	"lastCommitTime":    "time",
+	"lastCommitEpoch":   "time",
+	"lastCommitDate":    "time",
+	"lastCommitHash":    "hash",
+	"lastCommitAuthor":  "author",
+	"lastCommitEmail":   "author",
+	"lastCommitSubject": "subject",
+	"branchHead":        "branch",
+	"commitOid":         "branch",
+	"nearestTag":        "branch",
+	"tagDistance":       "branch",
+	"fetchNeeded":       "fetch",
+	"upstreamGone":      "fetch",
+	"branchUpstream":    "remote",
+	"defaultBranch":     "default",
+	"defaultAhead":      "default",
+	"defaultBehind":     "default",
+	"dirty":             "dirty",
+	"untracked":         "untracked",
+	"untrackedCount":    "untracked",
+	"stash":             "stash",
+	"stashCount":        "stash",
+	"stashAge":          "stash-age",
+	"stashOldestEpoch":  "stash-age",
+	"stashOldestDate":   "stash-age",
+	"parent":            "parent",
+}
+
+/*
+isFiltered returns if any filter is set
+
+	'thisConfig' holds the filters
+*/
+func isFiltered(thisConfig tConfig) bool {
+	return thisConfig.dirtyOnly || thisConfig.aheadOnly || thisConfig.behindOnly || thisConfig.divergedOnly ||
+		thisConfig.noUpstreamOnly || len(thisConfig.onBranch) > 0 || len(thisConfig.where) > 0
+}
+
+/*
+enableFilters validates --where expressions and enables retrieval of data the filters need
+
+	'thisConfig' (passed by reference) holds the filters, to be populated with flags
+*/
+func enableFilters(thisConfig *tConfig) ([]tCondition, error) {
+
+	var (
+		thisConditions []tCondition
+		thisIds        []string
+	)
+
+	if thisConfig.dirtyOnly {
+		thisIds = append(thisIds, "dirty")
+	}
+	if thisConfig.noUpstreamOnly {
+		thisIds = append(thisIds, "remote")
+	}
+	if len(thisConfig.onBranch) > 0 {
+		thisIds = append(thisIds, "branch")
+	}
+
+	for _, thisExpression := range thisConfig.where {
+		thisCondition, err := parseWhere(thisExpression)
+		if err != nil {
+			return nil, err
+		}
+		thisConditions = append(thisConditions, thisCondition)
+
+		if thisId, ok := whereColumns[thisCondition.field]; ok {
+			thisIds = append(thisIds, thisId)
+		}
+	}
+
+	for _, thisId := range thisIds {
+		thisColumn, err := getColumn(thisId)
+		if err != nil {
+			return nil, err
+		}
+		if thisColumn.enable != nil {
+			thisColumn.enable(thisConfig)
+		}
+	}
+
+	return thisConditions, nil
+}
+
+/*
+filterRepos returns repos matching every filter
+
+	'repos' slice of structures describing the repos
+	'thisConfig' holds the filters
+	'conditions' parsed --where expressions
+*/
+func filterRepos(repos []tRepo, thisConfig tConfig, conditions []tCondition) []tRepo {
+
+	var thisResult = make([]tRepo, 0, len(repos))
+
+	for _, thisRepo := range repos {
+		if isMatching(thisRepo, thisConfig, conditions) {
+			thisResult = append(thisResult, thisRepo)
+		}
+	}
+
+	if loggingLevel >= 2 {
+		logInfo.Printf("filterRepos: %d of %d repos matching.", len(thisResult), len(repos))
+	}
+
+	return thisResult
+}
+
+/*
+isMatching returns if repo matches every filter
+
+	'thisRepo' structure describing the repo
+	'thisConfig' holds the filters
+	'conditions' parsed --where expressions
+*/
+func isMatching(thisRepo tRepo, thisConfig tConfig, conditions []tCondition) bool {
+
+	switch {
+	case thisConfig.dirtyOnly && !thisRepo.Dirty:
+		return false
+	case thisConfig.aheadOnly && thisRepo.Ahead == 0:
+		return false
+	case thisConfig.behindOnly && thisRepo.Behind == 0:
+		return false
+	case thisConfig.divergedOnly && (thisRepo.Ahead == 0 || thisRepo.Behind == 0):
+		return false
+	case thisConfig.noUpstreamOnly && len(thisRepo.BranchUpstream) > 0:
+		return false
+	case len(thisConfig.onBranch) > 0 && thisRepo.BranchHead != thisConfig.onBranch:
+		return false
+	}
+
+	for _, thisCondition := range conditions {
+		if !thisCondition.isMet(thisRepo) {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+parseWhere returns condition of expression, e.g. `stashCount>0`, `branchHead==main`, `!dirty`
+
+	'expression' json field name, optionally followed by operator and value
+*/
+func parseWhere(expression string) (tCondition, error) {
+
+	var thisCondition tCondition
+
+	thisRest := strings.TrimSpace(expression)
+
+	if strings.HasPrefix(thisRest, "!") {
+		thisCondition.negate = true
+		thisRest = strings.TrimSpace(thisRest[1:])
+	}
+
+	/* Field name runs up to the operator */
+
+	thisEnd := strings.IndexFunc(thisRest, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if thisEnd < 0 {
+		thisEnd = len(thisRest)
+	}
+	thisCondition.field = thisRest[:thisEnd]
+	thisRest = strings.TrimSpace(thisRest[thisEnd:])
+
+	thisIndex, thisKind, err := getRepoField(thisCondition.field)
+	if err != nil {
+		return thisCondition, fmt.Errorf("where %q: %w", expression, err)
+	}
+	thisCondition.index = thisIndex
+
+	/* Bool field may be tested alone */
+
+	if len(thisRest) == 0 {
+		if thisKind != reflect.Bool {
+			return thisCondition, fmt.Errorf("where %q: operator expected", expression)
+		}
+		return thisCondition, nil
+	}
+
+	for _, thisOp := range whereOperators {
+		if strings.HasPrefix(thisRest, thisOp) {
+			thisCondition.op = thisOp
+			thisCondition.value = strings.TrimSpace(strings.TrimPrefix(thisRest, thisOp))
+			break
+		}
+	}
+	if len(thisCondition.op) == 0 {
+		return thisCondition, fmt.Errorf("where %q: unknown operator, expected one of: %s", expression, strings.Join(whereOperators, " "))
+	}
+
+	/* Value must suit the field */
+
+	thisSample := reflect.New(reflect.TypeOf(tRepo{})).Elem().Field(thisIndex)
+	if thisSample.Kind() == reflect.Pointer {
+		thisSample = reflect.New(thisSample.Type().Elem()) // Date present, for its value to be checked
+	}
+	if _, err := compareField(thisSample, thisCondition.value); err != nil && thisCondition.op != "~" {
+		return thisCondition, fmt.Errorf("where %q: %w", expression, err)
+	}
+
+	if thisCondition.op == "~" {
+		if thisKind != reflect.String {
+			return thisCondition, fmt.Errorf("where %q: ~ applies to text fields only", expression)
+		}
+		if thisCondition.regex, err = regexp.Compile(thisCondition.value); err != nil {
+			return thisCondition, fmt.Errorf("where %q: compiling regex failed. %w", expression, err)
+		}
+	}
+
+	return thisCondition, nil
+}
+
+/*
+isMet returns if repo meets the condition
+*/
+func (c tCondition) isMet(thisRepo tRepo) bool {
+
+	thisField := reflect.ValueOf(thisRepo).Field(c.index)
+
+	var isMet bool
+
+	switch c.op {
+	case "":
+		isMet = thisField.Bool()
+	case "~":
+		isMet = c.regex.MatchString(thisField.String())
+	default:
+		thisResult, err := compareField(thisField, c.value)
+		if err != nil {
+			return false // Validated by parseWhere
+		}
+		switch c.op {
+		case "==":
+			isMet = thisResult == 0
+		case "!=":
+			isMet = thisResult != 0
+		case "<":
+			isMet = thisResult < 0
+		case "<=":
+			isMet = thisResult <= 0
+		case ">":
+			isMet = thisResult > 0
+		case ">=":
+			isMet = thisResult >= 0
+		}
+	}
+
+	return isMet != c.negate
+}
+
+/*
+getRepoField returns index and kind of tRepo's field of given json name
+
+	'name' json name of the field
+*/
+func getRepoField(name string) (int, reflect.Kind, error) {
+
+	var (
+		thisType  = reflect.TypeOf(tRepo{})
+		thisNames []string
+	)
+
+	for i := 0; i < thisType.NumField(); i++ {
+		thisName := strings.Split(thisType.Field(i).Tag.Get("json"), ",")[0]
+		if thisName == name {
+			return i, thisType.Field(i).Type.Kind(), nil
+		}
+		thisNames = append(thisNames, thisName)
+	}
+
+	return 0, reflect.Invalid, fmt.Errorf("unknown field %q, expected one of: %s", name, strings.Join(thisNames, ","))
+}
+
+/*
+compareField returns -1, 0 or 1, as field is less, equal or greater than value
+
+	'thisField' value of tRepo's field
+	'value' text to be converted to field's type, dates as RFC3339 or YYYY-MM-DD
+*/
+func compareField(thisField reflect.Value, value string) (int, error) {
+
+	/* Missing date meets no condition */
+
+	if thisPointer, ok := thisField.Interface().(*time.Time); ok {
+		if thisPointer == nil {
+			return 0, fmt.Errorf("no date")
+		}
+		thisField = reflect.ValueOf(*thisPointer)
+	}
+
+	if thisTime, ok := thisField.Interface().(time.Time); ok {
+		thisValue, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			if thisValue, err = time.ParseInLocation(time.DateOnly, value, time.Local); err != nil {
+				return 0, fmt.Errorf("date expected, e.g. 2024-01-31. %w", err)
+			}
+		}
+		return thisTime.Compare(thisValue), nil
+	}
+
+	switch thisField.Kind() {
+	case reflect.Bool:
+		thisValue, err := strconv.ParseBool(value)
+		if err != nil {
+			return 0, fmt.Errorf("true or false expected. %w", err)
+		}
+		return compareBool(thisField.Bool(), thisValue), nil
+	case reflect.Int, reflect.Int64:
+		thisValue, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("number expected. %w", err)
+		}
+		return compareInt(thisField.Int(), thisValue), nil
+	case reflect.String:
+		return compareText(thisField.String(), value), nil
+	}
+
+	return 0, fmt.Errorf("field of %s kind cannot be compared", thisField.Kind())
+}
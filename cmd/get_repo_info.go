@@ -1,60 +1,123 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
+var errTimeout = errors.New("timed out") // Git command took longer than allowed
+
 /*
-getFetchNeeded returns if local needs sync with remote
+getFetchNeeded returns if local needs sync with remote, comparing remote's branch with its remote-tracking one
 
 	'thisRepo' structure (passed by refereferce) that contains initial data and to be populated
+	'config' rules of the command
 */
-func getFetchNeeded(thisRepo *tRepo) error {
+func getFetchNeeded(ctx context.Context, thisRepo *tRepo, config tConfig) error {
 
 	commCommand := "git"
-	repoUpstream := getStringRegex(`(.*)\/`, thisRepo.BranchUpstream) // repo name is before '/'
-	argsRemote := append([]string{},
-		"remote", "show", repoUpstream,
+	argsUpstream := append([]string{},
+		"for-each-ref", "--format=%(upstream:remotename)%09%(upstream:remoteref)%09%(upstream)", "refs/heads/"+thisRepo.BranchHead,
 	)
 
-	cmdOutput, err := runCommand(commCommand, argsRemote, thisRepo.TopLevelPath)
+	cmdOutput, err := runCommand(ctx, config, commCommand, argsUpstream, thisRepo.TopLevelPath)
 	if err != nil {
-		return fmt.Errorf("getting remote output failed. %w", err)
+		return fmt.Errorf("getting upstream output failed. %w", err)
 	}
 
 	/* Build the output */
 
 	if loggingLevel >= 3 {
-		logInfo.Printf("git status command output for %v:\n%s\n", thisRepo, cmdOutput)
+		logInfo.Printf("git for-each-ref command output for %v:\n%s\n", thisRepo, cmdOutput)
+	}
+
+	thisFields := strings.Split(cmdOutput, "\t")
+	if len(thisFields) != 3 || len(thisFields[0]) == 0 || len(thisFields[1]) == 0 {
+		return nil // No upstream of a remote
+	}
+	thisRemote, thisRemoteRef, thisTrackingRef := thisFields[0], thisFields[1], thisFields[2]
+
+	/* Query the remote */
+
+	argsLsRemote := append([]string{},
+		"ls-remote", "--", thisRemote, thisRemoteRef,
+	)
+
+	cmdOutput, err = runCommand(ctx, config, commCommand, argsLsRemote, thisRepo.TopLevelPath)
+	if err != nil {
+		return fmt.Errorf("getting ls-remote output failed. %w", err)
+	}
+
+	if loggingLevel >= 3 {
+		logInfo.Printf("git ls-remote command output for %v:\n%s\n", thisRepo, cmdOutput)
+	}
+
+	thisRemoteOid := getRemoteOid(cmdOutput, thisRemoteRef)
+	if len(thisRemoteOid) == 0 {
+		thisRepo.UpstreamGone = true
+		return nil
+	}
+
+	/* Missing remote-tracking branch means fetch is needed anyway */
+
+	argsLocal := append([]string{},
+		"rev-parse", "--verify", "-q", thisTrackingRef,
+	)
+
+	thisLocalOid, err := runCommand(ctx, config, commCommand, argsLocal, thisRepo.TopLevelPath)
+	if err != nil {
+		if isRunOver(ctx, err) {
+			return fmt.Errorf("getting tracking branch failed. %w", err)
+		}
+		thisLocalOid = ""
 	}
 
 	/* Construct boolean information */
 
-	thisRepo.FetchNeeded = !regexp.MustCompile(
-		`(?mU)^\s*` +
-			thisRepo.BranchHead +
-			`\s*pushes to [[:print:]]*\s*\(` + UP_TO_DATE + `\)$`).
-		MatchString(cmdOutput)
+	thisRepo.FetchNeeded = thisRemoteOid != thisLocalOid
+
 	return nil
 }
 
+/*
+getRemoteOid returns commit id of 'ref' found in `git ls-remote` output, empty if not found
+
+	'output' lines of `<oid> TAB <ref>`
+	'ref' full name of the ref
+*/
+func getRemoteOid(output string, ref string) string {
+
+	for _, thisLine := range strings.Split(output, "\n") {
+		if thisOid, thisRef, isFound := strings.Cut(thisLine, "\t"); isFound && thisRef == ref {
+			return thisOid
+		}
+	}
+
+	return ""
+}
+
 /*
 getOriginUrl returns remote's url
 
 	'thisRepo' structure (passed by refereferce) that contains initial data and to be populated
+	'config' rules of the command
 */
-func getOriginUrl(thisRepo *tRepo) error {
+func getOriginUrl(ctx context.Context, thisRepo *tRepo, config tConfig) error {
 
 	commCommand := "git"
 	argsRemote := append([]string{},
 		"config", "--get", "remote.origin.url",
 	)
 
-	cmdOutput, err := runCommand(commCommand, argsRemote, thisRepo.TopLevelPath)
+	cmdOutput, err := runCommand(ctx, config, commCommand, argsRemote, thisRepo.TopLevelPath)
 	if err != nil {
 		return fmt.Errorf("getting remote url failed. %w", err)
 	}
@@ -71,42 +134,300 @@ func getOriginUrl(thisRepo *tRepo) error {
 }
 
 /*
-GetLastCommitTime returns the last commit time for the repo
+getLastCommit populates time, epoch, short hash, author and subject of the last commit, with a single git log
 
-	'thisRepo' structure (passed by refereferce) that contains initial data and to be populated
-	'getEpoch' if result should be expressed in UNIX time
-	'config' dictates pretty format for human readable time
+	'thisRepo' structure (passed by refereferce) to be populated
+	'config' holds time format and kind of date
 */
-func getLastCommitTime(thisRepo *tRepo, getEpoch bool, config tConfig) error {
+func getLastCommit(ctx context.Context, thisRepo *tRepo, config tConfig) error {
 
-	var argsRemote []string
+	var (
+		thisDate  = "%c" // Committer date
+		thisOrder = "--date-order"
+	)
+
+	if config.dateKind.Value == "a" {
+		thisDate = "%a" // Author date, not altered by rebase
+		thisOrder = "--author-date-order"
+	}
 
 	commCommand := "git"
-	if getEpoch {
-		argsRemote = append([]string{},
-			"log", "--pretty=format:%ct", "--date-order", "-n 1",
-		)
-	} else {
-		argsRemote = append([]string{},
-			"log", "--pretty=format:%c"+config.timeFormat.Value, "--date-order", "-n 1",
-		)
+	argsRemote := append([]string{},
+		"log", thisOrder, "-n 1", fmt.Sprintf("--abbrev=%d", SHORT_OID_LEN),
+		"--pretty=format:"+thisDate+config.timeFormat.Value+"%x00"+thisDate+"I%x00%h%x00%an%x00%ae%x00%s",
+	)
+
+	cmdOutput, err := runCommand(ctx, config, commCommand, argsRemote, thisRepo.TopLevelPath)
+	if err != nil {
+		return fmt.Errorf("getting log output failed. %w", err)
+	}
+
+	/* Build the output */
+
+	if loggingLevel >= 3 {
+		logInfo.Printf("git log command output for %v:\n%q\n", thisRepo, cmdOutput)
+	}
+
+	return parseLastCommit(thisRepo, cmdOutput)
+}
+
+/*
+parseLastCommit populates last commit's fields of NUL separated git log output
+
+	'thisRepo' structure (passed by refereferce) to be populated
+	'output' time, strict ISO date, short hash, author name, author email and subject
+*/
+func parseLastCommit(thisRepo *tRepo, output string) error {
+
+	thisFields := strings.SplitN(output, "\x00", 6)
+	if len(thisFields) != 6 {
+		return fmt.Errorf("unexpected log output: %q", output)
+	}
+
+	thisDate, err := time.Parse(time.RFC3339, thisFields[1])
+	if err != nil {
+		return fmt.Errorf("parsing commit date failed. %w", err)
 	}
 
-	cmdOutput, err := runCommand(commCommand, argsRemote, thisRepo.TopLevelPath)
+	thisRepo.LastCommitTime = thisFields[0]
+	thisRepo.LastCommitDate = &thisDate
+	thisRepo.LastCommitEpoch = thisDate.Unix()
+	thisRepo.LastCommitHash = thisFields[2]
+	thisRepo.LastCommitAuthor = thisFields[3]
+	thisRepo.LastCommitEmail = thisFields[4]
+	thisRepo.LastCommitSubject = thisFields[5]
+
+	return nil
+}
+
+/*
+getStashAge populates time of the oldest stash
+
+	'thisRepo' structure (passed by refereferce) to be populated
+	'config' holds time format
+*/
+func getStashAge(ctx context.Context, thisRepo *tRepo, config tConfig) error {
+
+	commCommand := "git"
+	argsRemote := append([]string{},
+		"log", "-g", "--pretty=format:%c"+config.timeFormat.Value+"%x00%cI", "refs/stash",
+	)
+
+	cmdOutput, err := runCommand(ctx, config, commCommand, argsRemote, thisRepo.TopLevelPath)
 	if err != nil {
-		return fmt.Errorf("getting last commit time failed. %w", err)
+		return fmt.Errorf("getting stash list failed. %w", err)
 	}
 
 	/* Build the output */
 
 	if loggingLevel >= 3 {
-		logInfo.Printf("git status command output for %v:\n%s\n", thisRepo, cmdOutput)
+		logInfo.Printf("git log command output for %v:\n%q\n", thisRepo, cmdOutput)
 	}
 
-	if getEpoch {
-		thisRepo.LastCommitEpoch = cmdOutput
-	} else {
-		thisRepo.LastCommitTime = cmdOutput
+	return parseStashAge(thisRepo, cmdOutput)
+}
+
+/*
+parseStashAge populates oldest stash's fields of git log output, the oldest stash being listed last
+
+	'thisRepo' structure (passed by refereferce) to be populated
+	'output' lines of time and strict ISO date, NUL separated
+*/
+func parseStashAge(thisRepo *tRepo, output string) error {
+
+	if len(output) == 0 {
+		return nil // No stash
+	}
+
+	thisLines := strings.Split(output, "\n")
+	thisFields := strings.SplitN(thisLines[len(thisLines)-1], "\x00", 2)
+	if len(thisFields) != 2 {
+		return fmt.Errorf("unexpected stash log output: %q", output)
+	}
+
+	thisDate, err := time.Parse(time.RFC3339, thisFields[1])
+	if err != nil {
+		return fmt.Errorf("parsing stash date failed. %w", err)
+	}
+
+	thisRepo.StashAge = thisFields[0]
+	thisRepo.StashOldestDate = &thisDate
+	thisRepo.StashOldestEpoch = thisDate.Unix()
+
+	return nil
+}
+
+/*
+getRepoLayout returns if repo is bare, or is a linked worktree of the main one
+
+	'thisRepo' structure (passed by refereferce) that contains initial data and to be populated
+	'config' rules of the command
+*/
+func getRepoLayout(ctx context.Context, thisRepo *tRepo, config tConfig) error {
+
+	commCommand := "git"
+	argsLayout := append([]string{},
+		"rev-parse", "--git-dir", "--git-common-dir", "--is-bare-repository",
+	)
+
+	cmdOutput, err := runCommand(ctx, config, commCommand, argsLayout, thisRepo.TopLevelPath)
+	if err != nil {
+		return fmt.Errorf("getting layout output failed. %w", err)
+	}
+
+	/* Build the output */
+
+	if loggingLevel >= 3 {
+		logInfo.Printf("git rev-parse command output for %v:\n%s\n", thisRepo, cmdOutput)
+	}
+
+	return parseRepoLayout(thisRepo, cmdOutput)
+}
+
+/*
+parseRepoLayout populates layout from `git rev-parse --git-dir --git-common-dir --is-bare-repository` output
+
+	'thisRepo' structure (passed by refereferce) that contains top level path and to be populated
+	'output' text to be parsed, with paths relative to the top level one
+*/
+func parseRepoLayout(thisRepo *tRepo, output string) error {
+
+	thisLines := strings.Split(output, "\n")
+	if len(thisLines) != 3 {
+		return fmt.Errorf("unexpected layout output: %s", output)
+	}
+
+	/* Git before 2.31 lacks --path-format, paths relative to where it was run */
+
+	getAbsolute := func(thisPath string) string {
+		if filepath.IsAbs(thisPath) {
+			return filepath.Clean(thisPath)
+		}
+		return filepath.Join(thisRepo.TopLevelPath, thisPath)
+	}
+
+	thisRepo.GitDir = getAbsolute(thisLines[0])
+	thisRepo.Bare = thisLines[2] == "true"
+
+	/* Linked worktree keeps its git dir within the common one */
+
+	if thisCommonDir := getAbsolute(thisLines[1]); thisCommonDir != thisRepo.GitDir {
+		if filepath.Base(thisCommonDir) == ".git" {
+			thisRepo.MainRepo = filepath.Dir(thisCommonDir)
+		} else {
+			thisRepo.MainRepo = thisCommonDir // Worktree of a bare repo
+		}
+	}
+
+	return nil
+}
+
+/*
+getNearestTag returns the nearest tag reachable from HEAD, and number of commits on top of it
+
+	'thisRepo' structure (passed by refereferce) that contains initial data and to be populated
+	'config' rules of the command
+*/
+func getNearestTag(ctx context.Context, thisRepo *tRepo, config tConfig) error {
+
+	commCommand := "git"
+	argsDescribe := append([]string{},
+		"describe", "--tags", "--long",
+	)
+
+	/* No tags makes describe fail, which is fine */
+
+	cmdOutput, err := runCommand(ctx, config, commCommand, argsDescribe, thisRepo.TopLevelPath)
+	if err != nil {
+		if isRunOver(ctx, err) {
+			return fmt.Errorf("getting describe output failed. %w", err)
+		}
+		if loggingLevel >= 2 {
+			logInfo.Printf("no tag found for %s. %s", thisRepo.TopLevelPath, err)
+		}
+		return nil
+	}
+
+	/* Build the output, e.g. `v1.4.2-3-ga1b2c3d` */
+
+	matches := regexp.MustCompile(`^(.+)-(\d+)-g[0-9a-f]+$`).FindStringSubmatch(cmdOutput)
+	if len(matches) == 0 {
+		return fmt.Errorf("unexpected describe output: %s", cmdOutput)
+	}
+
+	thisRepo.NearestTag = matches[1]
+	if thisRepo.TagDistance, err = strconv.Atoi(matches[2]); err != nil {
+		return fmt.Errorf("converting tag distance to int failed. %w", err)
+	}
+
+	return nil
+}
+
+/*
+getOperation returns git operation left in progress, looking at the git dir only
+
+	'gitDir' full path of git's database
+*/
+func getOperation(gitDir string) string {
+
+	isPresent := func(name string) bool {
+		_, err := os.Stat(filepath.Join(gitDir, name))
+		return err == nil
+	}
+
+	switch {
+	case isPresent("rebase-merge"), isPresent("rebase-apply"):
+		if isPresent(filepath.Join("rebase-apply", "applying")) {
+			return AM_OPERATION
+		}
+		return REBASE_OPERATION
+	case isPresent("MERGE_HEAD"):
+		return MERGE_OPERATION
+	case isPresent("CHERRY_PICK_HEAD"):
+		return CHERRY_PICK_OPERATION
+	case isPresent("REVERT_HEAD"):
+		return REVERT_OPERATION
+	case isPresent("BISECT_LOG"):
+		return BISECT_OPERATION
+	}
+
+	return ""
+}
+
+/*
+getBareStatus returns status of bare repo, that has branches but no work tree
+
+	'thisRepo' structure (passed by refereferce) that contains initial data and to be populated
+	'config' dictates how data should be populated
+*/
+func getBareStatus(ctx context.Context, thisRepo *tRepo, config tConfig) error {
+
+	commCommand := "git"
+	argsHead := append([]string{},
+		"symbolic-ref", "--short", "-q", "HEAD",
+	)
+
+	/* Detached HEAD makes symbolic-ref fail */
+
+	thisHead, err := runCommand(ctx, config, commCommand, argsHead, thisRepo.TopLevelPath)
+	if err != nil {
+		if isRunOver(ctx, err) {
+			return fmt.Errorf("getting branch head failed. %w", err)
+		}
+		thisHead = DETACHED_HEAD
+	}
+
+	if config.showBranchHead || config.showFetchNeeded {
+		thisRepo.BranchHead = thisHead
+	}
+
+	if (config.showBranchUpstream || config.showUrl || config.showFetchNeeded) && err == nil {
+		argsUpstream := append([]string{},
+			"for-each-ref", "--format=%(upstream:short)", "refs/heads/"+thisHead,
+		)
+		if thisRepo.BranchUpstream, err = runCommand(ctx, config, commCommand, argsUpstream, thisRepo.TopLevelPath); err != nil {
+			return fmt.Errorf("getting upstream failed. %w", err)
+		}
 	}
 
 	return nil
@@ -118,12 +439,12 @@ getRepoStatus returns repo status
 	'thisRepo' structure (passed by refereferce) that contains initial data and to be populated
 	'config' dictates how data should be populated
 */
-func getRepoStatus(thisRepo *tRepo, config tConfig) error {
+func getRepoStatus(ctx context.Context, thisRepo *tRepo, config tConfig) error {
 
 	commCommand := "git"
 	argsStatus := getArgsStatus(config)
 
-	cmdOutput, err := runCommand(commCommand, argsStatus, thisRepo.TopLevelPath)
+	cmdOutput, err := runCommand(ctx, config, commCommand, argsStatus, thisRepo.TopLevelPath)
 	if err != nil {
 		return fmt.Errorf("getting status output failed. %w", err)
 	}
@@ -134,70 +455,259 @@ func getRepoStatus(thisRepo *tRepo, config tConfig) error {
 		logInfo.Printf("git status command output for %v:\n%s\n", thisRepo, cmdOutput)
 	}
 
-	/* Construct ahead / behind symbol */
+	thisStatus, err := parsePorcelainV2(cmdOutput)
+	if err != nil {
+		return fmt.Errorf("parsing status output failed. %w", err)
+	}
 
-	regex := regexp.MustCompile(`(?mU)^# branch.ab \+(\d*) \-(\d*)$`) // Capture 2 numbers
-	matches := regex.FindStringSubmatch(cmdOutput)
-	if len(matches) > 0 {
+	/* Construct ahead / behind symbol */
 
-		/* Convert both to int */
+	if thisStatus.hasAB {
 
-		if thisRepo.Ahead, err = strconv.Atoi(matches[1]); err != nil {
-			return fmt.Errorf("converting ahead to int failed. %w", err)
-		}
-		if thisRepo.Behind, err = strconv.Atoi(matches[2]); err != nil {
-			return fmt.Errorf("converting behind to int failed. %w", err)
-		}
+		thisRepo.Ahead = thisStatus.ahead
+		thisRepo.Behind = thisStatus.behind
 
 		/* Calculate StatusAB using ahead & behind values */
 
-		if thisRepo.Ahead == 0 && thisRepo.Behind == 0 {
-			thisRepo.StatusAB = SYNCED_CHAR
-		} else if thisRepo.Ahead == 0 {
-			thisRepo.StatusAB = REMOTE_AHEAD_CHAR
-		} else if thisRepo.Behind == 0 {
-			thisRepo.StatusAB = LOCAL_AHEAD_CHAR
-		} else {
-			thisRepo.StatusAB = DIVERGED_CHAR
-		}
+		thisRepo.StatusAB = getStatusAB(thisRepo.Ahead, thisRepo.Behind)
 	}
 
+	/* Construct counts */
+
+	thisRepo.Staged = thisStatus.staged
+	thisRepo.Modified = thisStatus.modified
+	thisRepo.Deleted = thisStatus.deleted
+	thisRepo.Renamed = thisStatus.renamed
+	thisRepo.Unmerged = thisStatus.unmerged
+	thisRepo.UntrackedCount = thisStatus.untracked
+
 	/* Construct boolean information */
 
 	if config.showDirty {
-		thisRepo.Dirty = regexp.MustCompile(`(?m)^[^#?]`).MatchString(cmdOutput) // Dirty, does not beging with `#` nor `?`
+		thisRepo.Dirty = thisStatus.isDirty()
 	}
 	if config.showUntracked {
-		thisRepo.Untracked = regexp.MustCompile(`(?m)^\?`).MatchString(cmdOutput) // Untracked, begins with `?`
+		thisRepo.Untracked = thisStatus.untracked > 0
 	}
 	if config.showStash {
-		thisRepo.Stash = regexp.MustCompile(`(?mU)^# stash (\d*)$`).MatchString(cmdOutput)
+		thisRepo.Stash = thisStatus.stash > 0
+		thisRepo.StashCount = thisStatus.stash
 	}
 
-	/* Construct branch information */
+	/* Construct branch information, commit id telling if there are commits at all */
 
-	if config.showBranchHead || config.showFetchNeeded {
-		thisRepo.BranchHead = getStringRegex(`(?mU)^# branch.head (.+)$`, cmdOutput)
+	thisRepo.CommitOid = thisStatus.branchOid
+	if config.showBranchHead || config.showFetchNeeded || config.showDefaultAB {
+		thisRepo.BranchHead = thisStatus.branchHead
 	}
 	if config.showBranchUpstream || config.showUrl || config.showFetchNeeded {
-		thisRepo.BranchUpstream = getStringRegex(`(?mU)^# branch.upstream (.+)$`, cmdOutput)
+		thisRepo.BranchUpstream = thisStatus.branchUpstream
 	}
 
 	return nil
 
 }
 
-func runCommand(thisCommand string, thisArgs []string, thisDir string) (string, error) {
-	cmd := exec.Command(thisCommand, thisArgs...)
+/*
+getStatusAB returns ahead / behind status string
+
+	'ahead' commits on local side only
+	'behind' commits on the other side only
+*/
+func getStatusAB(ahead int, behind int) string {
+
+	switch {
+	case ahead == 0 && behind == 0:
+		return SYNCED_CHAR
+	case ahead == 0:
+		return REMOTE_AHEAD_CHAR
+	case behind == 0:
+		return LOCAL_AHEAD_CHAR
+	default:
+		return DIVERGED_CHAR
+	}
+}
+
+/*
+getDefaultAB populates default branch and commits ahead / behind it
+
+	'thisRepo' structure (passed by refereferce) that contains initial data and to be populated
+	'config' rules of the command
+*/
+func getDefaultAB(ctx context.Context, thisRepo *tRepo, config tConfig) error {
+
+	thisDefault, err := getDefaultBranch(ctx, thisRepo, config)
+	if err != nil {
+		return err
+	}
+	if len(thisDefault) == 0 {
+		return nil // Unknown default branch is no failure
+	}
+
+	commCommand := "git"
+	argsCount := append([]string{},
+		"rev-list", "--left-right", "--count", "HEAD..."+thisDefault, "--",
+	)
+
+	cmdOutput, err := runCommand(ctx, config, commCommand, argsCount, thisRepo.TopLevelPath)
+	if err != nil {
+		return fmt.Errorf("counting commits against %s failed. %w", thisDefault, err)
+	}
+
+	if loggingLevel >= 3 {
+		logInfo.Printf("git rev-list command output for %v:\n%s\n", thisRepo, cmdOutput)
+	}
+
+	if thisRepo.DefaultAhead, thisRepo.DefaultBehind, err = parseLeftRight(cmdOutput); err != nil {
+		return err
+	}
+	thisRepo.DefaultBranch = thisDefault
+
+	return nil
+}
+
+/*
+getDefaultBranch returns `gitas.defaultBranch` config value, or branch the remote's HEAD points to, empty when unknown
+
+	'thisRepo' structure that contains initial data
+	'config' rules of the command
+*/
+func getDefaultBranch(ctx context.Context, thisRepo *tRepo, config tConfig) (string, error) {
+
+	commCommand := "git"
+
+	/* Configured one takes precedence */
+
+	argsConfig := append([]string{},
+		"config", "--default", "", "--get", "gitas.defaultBranch",
+	)
+
+	thisDefault, err := runCommand(ctx, config, commCommand, argsConfig, thisRepo.TopLevelPath)
+	if err != nil {
+		return "", fmt.Errorf("getting configured default branch failed. %w", err)
+	}
+	if len(thisDefault) > 0 {
+		return thisDefault, nil
+	}
+
+	/* Remote of the current branch, origin when none */
+
+	thisRemote := "origin"
+	if thisRepo.BranchHead != DETACHED_HEAD {
+		argsRemote := append([]string{},
+			"config", "--default", "origin", "--get", "branch."+thisRepo.BranchHead+".remote",
+		)
+		if thisRemote, err = runCommand(ctx, config, commCommand, argsRemote, thisRepo.TopLevelPath); err != nil {
+			return "", fmt.Errorf("getting remote failed. %w", err)
+		}
+	}
+
+	/* Symbolic ref, as set by clone or `git remote set-head` */
+
+	argsHead := append([]string{},
+		"for-each-ref", "--format=%(symref:short)", "refs/remotes/"+thisRemote+"/HEAD",
+	)
+
+	if thisDefault, err = runCommand(ctx, config, commCommand, argsHead, thisRepo.TopLevelPath); err != nil {
+		return "", fmt.Errorf("getting remote's HEAD failed. %w", err)
+	}
+
+	return thisDefault, nil
+}
+
+/*
+parseLeftRight returns counts of `git rev-list --left-right --count` output
+
+	'output' text to be parsed, e.g. `3	1`
+*/
+func parseLeftRight(output string) (int, int, error) {
+
+	thisFields := strings.Fields(output)
+	if len(thisFields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", output)
+	}
+
+	thisLeft, err := strconv.Atoi(thisFields[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("converting left count to int failed. %w", err)
+	}
+
+	thisRight, err := strconv.Atoi(thisFields[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("converting right count to int failed. %w", err)
+	}
+
+	return thisLeft, thisRight, nil
+}
+
+/*
+runCommand returns trimmed standard output of the command
+
+	'ctx' cancels the command, when done
+	'config' rules of the command
+	'thisCommand' command to be run
+	'thisArgs' its arguments
+	'thisDir' directory to be run in
+*/
+func runCommand(ctx context.Context, config tConfig, thisCommand string, thisArgs []string, thisDir string) (string, error) {
+
+	ctx, cancel := getCommandContext(ctx, config)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, thisCommand, thisArgs...)
 	cmd.Dir = thisDir
+	cmd.Env = getCommandEnv()
+	cmd.WaitDelay = time.Duration(WAIT_DELAY_MS) * time.Millisecond // Children, like ssh, may hold the output open
+
 	out_bytes, err := cmd.Output()
 	if err != nil {
+		if ctxErr := ctx.Err(); errors.Is(ctxErr, context.DeadlineExceeded) {
+			return "", fmt.Errorf("running %s %s failed. %w", thisCommand, strings.Join(thisArgs, " "), errTimeout)
+		} else if ctxErr != nil {
+			return "", fmt.Errorf("running %s %s failed. %w", thisCommand, strings.Join(thisArgs, " "), ctxErr)
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("getting cmd output failed. %w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
 		return "", fmt.Errorf("getting cmd output failed. %w", err)
 	}
 
 	return strings.TrimSpace(string(out_bytes)), nil
 }
 
+/*
+isRunOver returns if command failed for running out of time or being cancelled, rather than on its own
+
+	'ctx' context the command was run with
+	'err' error returned by the command
+*/
+func isRunOver(ctx context.Context, err error) bool {
+	return errors.Is(err, errTimeout) || ctx.Err() != nil
+}
+
+/*
+getCommandContext returns context limiting a single git command to --timeout
+
+	'ctx' parent context
+	'config' rules of the command
+*/
+func getCommandContext(ctx context.Context, config tConfig) (context.Context, context.CancelFunc) {
+
+	if config.gitTimeout > 0 {
+		return context.WithTimeout(ctx, config.gitTimeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+/*
+getCommandEnv returns environment for git commands, which output must not be localized
+*/
+func getCommandEnv() []string {
+	return append(os.Environ(), "LC_ALL=C")
+}
+
 /*
 getArgsStatus returns git status command arguments
 
@@ -219,25 +729,10 @@ func getArgsStatus(config tConfig) []string {
 			"--untracked-files=no",
 		)
 	}
-	if config.lookForSubGits {
+	if !config.lookForSubGits { // Submodules are left out, unless nested repos are searched
 		args = append(args,
 			"--ignore-submodules=all",
 		)
 	}
 	return args
 }
-
-/*
-getStringRegex returns first regex 'expression' match within the 'input'
-
-	'expression' regex expression
-	'input' string to be searched
-*/
-func getStringRegex(expression string, input string) string {
-	regex := regexp.MustCompile(expression)
-	matches := regex.FindStringSubmatch(input)
-	if len(matches) > 0 {
-		return matches[1]
-	}
-	return ""
-}
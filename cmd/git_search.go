@@ -1,57 +1,168 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/briandowns/spinner"
 )
 
 /*
-getReposDictionary returns a slice of Repos found under the 'dirName'
+getReposDictionary returns a slice of Repos found under the 'dirNames', and paths that could not be read.
+When run is over while searching, the repos found so far are returned along with the error
 
-	'dirName' path to be searched
+	'dirNames' paths to be searched
 	'config' rules of the search
 */
-func getReposDictionary(dirName string, config tConfig) ([]tRepo, error) {
+func getReposDictionary(ctx context.Context, dirNames []string, config tConfig) ([]tRepo, []string, error) {
 
-	var (
-		allRepos    []tRepo
-		thisSpinner *spinner.Spinner = nil
-	)
+	var allRepos []tRepo
 
 	/* Get repos slice */
 
-	gitsSlice, err := findRepos(dirName, config.lookForSubGits)
+	gitsSlice, unreadable, err := getGitsSlice(ctx, dirNames, config)
 	if err != nil {
-		return nil, fmt.Errorf("finding repos failed. %w", err)
+		err = fmt.Errorf("getting repos slice failed. %w", err)
+
+		/* Report what was found, when run is over */
+
+		if ctx.Err() == nil {
+			return nil, nil, err
+		}
 	}
+
+	/* Setting names of all repos */
+
+	allRepos = getNamedRepos(gitsSlice)
+
+	/* Query each repo */
+
+	queryRepos(ctx, allRepos, config)
+
+	return allRepos, unreadable, err
+}
+
+/*
+queryRepos populates repos with their status, recording failures and interruptions on them
+
+	'ctx' stops the queries, when done
+	'allRepos' repos (passed by reference) with names set
+	'config' rules of the queries
+*/
+func queryRepos(ctx context.Context, allRepos []tRepo, config tConfig) {
+
+	var thisSpinner *spinner.Spinner = nil
+
+	/* Main loop, run by a bounded pool of workers */
+
+	thisSpinner = spinner.New(spinner.CharSets[14], time.Duration(SPINNER_MS)*time.Millisecond, spinner.WithWriter(os.Stderr),
+		spinner.WithSuffix(" Retrieving status of repositories\n"))
+	thisSpinner.Start() // Starting spinner to show visual work
+
+	var (
+		thisJobs = getJobs(config.jobs, len(allRepos))
+		indexes  = make(chan int)
+		errs     = make([]error, len(allRepos)) // Kept by index, for deterministic reporting
+		done     int
+		wg       sync.WaitGroup
+	)
+
 	if loggingLevel >= 2 {
-		logInfo.Printf("%d repos found.", len(gitsSlice))
+		logInfo.Printf("%d workers started.", thisJobs)
+	}
+
+	for w := 0; w < thisJobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = getRepoInfo(ctx, &allRepos[i], config)
+
+				/* Spinner is shared by all workers */
+
+				thisSpinner.Lock()
+				done++
+				thisSpinner.Suffix = fmt.Sprintf(" %d/%d %s", done, len(allRepos), allRepos[i].ShortName)
+				thisSpinner.Unlock()
+			}
+		}()
+	}
+
+	/* Stop dispatching when run is over, what is in flight gets cancelled */
+
+	thisStarted := 0
+thisDispatch:
+	for ; thisStarted < len(allRepos); thisStarted++ {
+		select {
+		case indexes <- thisStarted:
+		case <-ctx.Done():
+			break thisDispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	thisSpinner.Stop()
+
+	for i := thisStarted; i < len(allRepos); i++ {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			errs[i] = fmt.Errorf("not started. %w", errTimeout)
+		} else {
+			errs[i] = fmt.Errorf("not started. %w", ctx.Err())
+		}
 	}
 
+	/* Record errors on the failing repos, so the others are still reported */
+
+	for i, err := range errs {
+
+		/* Interrupted repos are not failing, just partially retrieved */
+
+		if errors.Is(err, context.Canceled) {
+			allRepos[i].Incomplete = true
+			continue
+		}
+
+		if err != nil {
+			allRepos[i].Error = err.Error()
+			allRepos[i].TimedOut = errors.Is(err, errTimeout)
+			if loggingLevel >= 1 {
+				logWarning.Printf("%s: %s", allRepos[i].TopLevelPath, allRepos[i].Error)
+			}
+		}
+	}
+}
+
+/*
+getNamedRepos returns repos of given top-level paths, with their names set
+
+	'gitsSlice' top-level paths of the repos
+*/
+func getNamedRepos(gitsSlice []string) []tRepo {
+
+	var allRepos = make([]tRepo, len(gitsSlice))
+
 	/* Get common path prefix for all the repos */
 
-	commonPrefix := commonPrefix(os.PathSeparator, gitsSlice) + string(os.PathSeparator)
+	commonPrefix := commonPrefix(os.PathSeparator, gitsSlice)
 	if loggingLevel >= 2 {
 		logInfo.Printf("common prefix: %s", commonPrefix)
 	}
 
-	/* Main loop */
+	for i, thisGit := range gitsSlice {
 
-	thisSpinner = spinner.New(spinner.CharSets[14], time.Duration(SPINNER_MS)*time.Millisecond, spinner.WithWriter(os.Stderr),
-		spinner.WithSuffix(" Retrieving status of repositories\n"))
-	thisSpinner.Start() // Starting spinner to show visual work
-
-	for _, thisGit := range gitsSlice {
-
-		var thisRepo tRepo
+		thisRepo := &allRepos[i]
 
 		/* Putting full path */
 
@@ -60,9 +171,9 @@ func getReposDictionary(dirName string, config tConfig) ([]tRepo, error) {
 		/* Setting names */
 
 		// Least significant segment
-		thisRepo.ShortName = thisRepo.TopLevelPath[strings.LastIndex(thisRepo.TopLevelPath, string(os.PathSeparator))+1:]
-		// Removing commonPrefix
-		thisRepo.UniqueName = strings.ReplaceAll(thisRepo.TopLevelPath, commonPrefix, "")
+		thisRepo.ShortName = filepath.Base(thisRepo.TopLevelPath)
+		// Relative to commonPrefix
+		thisRepo.UniqueName = getUniqueName(thisRepo.TopLevelPath, commonPrefix)
 		// Most significant segment from UniqueName
 		thisRepo.TopLevelGroup = strings.Split(thisRepo.UniqueName, string(os.PathSeparator))[0]
 
@@ -71,86 +182,326 @@ func getReposDictionary(dirName string, config tConfig) ([]tRepo, error) {
 		if len(gitsSlice) == 1 {
 			thisRepo.UniqueName = thisRepo.ShortName
 		}
+	}
+
+	return allRepos
+}
+
+/*
+getUniqueName returns path relative to the prefix, least significant segment when path is the prefix itself
 
-		thisSpinner.Suffix = " " + thisRepo.ShortName
+	'topLevelPath' full path of the repo
+	'prefix' common prefix of all repos, empty when they share the root only
+*/
+func getUniqueName(topLevelPath string, prefix string) string {
 
-		/* Get most part of repo's status */
+	if len(prefix) == 0 {
+		prefix = string(os.PathSeparator) // Roots sharing nothing but the root
+	}
 
-		if err := getRepoStatus(&thisRepo, config); err != nil {
-			return nil, fmt.Errorf("getting repos status failed. %w", err)
+	thisRel, err := filepath.Rel(prefix, topLevelPath)
+	if err != nil || strings.HasPrefix(thisRel, "..") {
+		return topLevelPath // Different volumes
+	}
+
+	if thisRel == "." {
+		return filepath.Base(topLevelPath)
+	}
+
+	return thisRel
+}
+
+/*
+getRepoInfo populates all the information about a single repo
+
+	'thisRepo' structure (passed by refereferce) that contains initial data and to be populated
+	'config' rules of the population
+*/
+func getRepoInfo(ctx context.Context, thisRepo *tRepo, config tConfig) error {
+
+	/* Get repo's layout */
+
+	if err := getRepoLayout(ctx, thisRepo, config); err != nil {
+		return fmt.Errorf("getting repos layout failed. %w", err)
+	}
+
+	/* Get most part of repo's status, bare repos have no work tree */
+
+	if thisRepo.Bare {
+		if err := getBareStatus(ctx, thisRepo, config); err != nil {
+			return fmt.Errorf("getting bare repos status failed. %w", err)
+		}
+	} else {
+		if err := getRepoStatus(ctx, thisRepo, config); err != nil {
+			return fmt.Errorf("getting repos status failed. %w", err)
 		}
+		thisRepo.Operation = getOperation(thisRepo.GitDir)
+	}
 
-		/* Get fetch needed */
+	/* Get nearest tag of detached HEAD */
 
-		if config.showFetchNeeded && len(thisRepo.BranchUpstream) > 0 {
-			if err = getFetchNeeded(&thisRepo); err != nil {
-				return nil, fmt.Errorf("getting remote sync need failed. %w", err)
-			}
+	if thisRepo.BranchHead == DETACHED_HEAD && len(thisRepo.CommitOid) > 0 {
+		if err := getNearestTag(ctx, thisRepo, config); err != nil {
+			return fmt.Errorf("getting nearest tag failed. %w", err)
 		}
+	}
+
+	/* Get superproject of a submodule */
+
+	if (config.showSubmodules || config.showParent) && !thisRepo.Bare {
+		var err error
+		if thisRepo.Parent, err = getSuperproject(ctx, thisRepo.TopLevelPath, config); err != nil {
+			return fmt.Errorf("getting superproject failed. %w", err)
+		}
+	}
+
+	/* Get repo's url */
+
+	if config.showUrl && len(thisRepo.BranchUpstream) > 0 {
+		if err := getOriginUrl(ctx, thisRepo, config); err != nil {
+			return fmt.Errorf("getting origin url failed. %w", err)
+		}
+	}
 
-		/* Get repo's url */
+	/* Get ahead / behind default branch, unless no commits yet */
 
-		if config.showUrl && len(thisRepo.BranchUpstream) > 0 {
-			if err = getOriginUrl(&thisRepo); err != nil {
-				return nil, fmt.Errorf("getting origin url failed. %w", err)
+	if config.showDefaultAB && !thisRepo.Bare && thisRepo.CommitOid != INITIAL_OID {
+		if err := getDefaultAB(ctx, thisRepo, config); err != nil {
+			return fmt.Errorf("getting ahead / behind default branch failed. %w", err)
+		}
+	}
+
+	/* Get repo's oldest stash */
+
+	if config.showStashAge && thisRepo.StashCount > 0 {
+		if err := getStashAge(ctx, thisRepo, config); err != nil {
+			return fmt.Errorf("getting stash age failed. %w", err)
+		}
+	}
+
+	/* Get repo's last commit, its epoch being needed for sorting, unless no commits yet */
+
+	if (config.showCommitTime || config.showCommitHash || config.showCommitAuthor || config.showCommitSubject ||
+		isSortedBy(config, "time")) && thisRepo.CommitOid != INITIAL_OID {
+		if err := getLastCommit(ctx, thisRepo, config); err != nil {
+			return fmt.Errorf("getting last commit failed. %w", err)
+		}
+	}
+
+	/* Get fetch needed, the only network-bound call, last */
+
+	if config.showFetchNeeded && len(thisRepo.BranchUpstream) > 0 {
+		if err := getFetchNeeded(ctx, thisRepo, config); err != nil {
+			return fmt.Errorf("getting remote sync need failed. %w", err)
+		}
+	}
+
+	return nil
+}
+
+/*
+getJobs returns the number of workers to be run
+
+	'jobs' requested number of workers, CPU count if less than 1
+	'repoCount' number of repos to be processed
+*/
+func getJobs(jobs int, repoCount int) int {
+
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+
+	/* No point in idle workers */
+
+	if jobs > repoCount {
+		jobs = repoCount
+	}
+
+	return jobs
+}
+
+/*
+getGitsSlice returns the slice of unique top-level git repo paths found under all 'dirNames' or listed in a file,
+and the slice of paths that could not be read
+
+	'dirNames' paths to search and their children
+	'config' rules of the search
+*/
+func getGitsSlice(ctx context.Context, dirNames []string, config tConfig) ([]string, []string, error) {
+
+	var (
+		thisResult     []string
+		thisUnreadable []string
+		thisSeen       = map[string]bool{} // Top levels already appended
+	)
+
+	/* Append only the first occurrence */
+
+	appendUnique := func(gits []string) {
+		for _, thisGit := range gits {
+			if !thisSeen[thisGit] {
+				thisSeen[thisGit] = true
+				thisResult = append(thisResult, thisGit)
 			}
 		}
+	}
+
+	/* Search the trees */
+
+	for _, thisDir := range dirNames {
+		thisGits, unreadable, err := findReposCached(ctx, thisDir, config)
+		if err != nil {
 
-		/* Get repo's time */
+			/* Keep what was found, when run is over */
 
-		if config.showCommitTime {
-			if err = getLastCommitTime(&thisRepo, false, config); err != nil {
-				return nil, fmt.Errorf("getting last commit time failed. %w", err)
+			if ctx.Err() != nil {
+				appendUnique(thisGits)
+				return thisResult, append(thisUnreadable, unreadable...), fmt.Errorf("finding repos in %s failed. %w", thisDir, err)
 			}
+			return nil, nil, fmt.Errorf("finding repos in %s failed. %w", thisDir, err)
 		}
+		appendUnique(thisGits)
+		thisUnreadable = append(thisUnreadable, unreadable...)
+	}
+
+	/* Take the listed repos */
 
-		/* Get repo's epoch */
+	if len(config.fromFile) > 0 {
+		thisListed, err := readRepoList(config.fromFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading repo list failed. %w", err)
+		}
 
-		if config.sortOrder.Value == "t" {
-			if err = getLastCommitTime(&thisRepo, true, config); err != nil {
-				return nil, fmt.Errorf("getting last commit epoch failed. %w", err)
+		for _, thisPath := range thisListed {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return thisResult, thisUnreadable, fmt.Errorf("reading repo list stopped. %w", ctxErr)
 			}
+			if thisBare, isBare := getBareRoot(ctx, thisPath, config); isBare {
+				appendUnique([]string{thisBare})
+				continue
+			}
+			gitTopLevel, err := getGitTopLevel(ctx, thisPath, config)
+			if err != nil {
+				logWarning.Printf("skipping %s, not a repo. %s", thisPath, err)
+				continue
+			}
+			appendUnique([]string{gitTopLevel})
 		}
+	}
 
-		/* Append thisRepo to allRepos */
+	if loggingLevel >= 2 {
+		logInfo.Printf("%d repos found.", len(thisResult))
+	}
+
+	return thisResult, thisUnreadable, nil
+}
 
-		allRepos = append(allRepos, thisRepo)
+/*
+readRepoList returns paths listed in the file, one per line, skipping blank lines and `#` comments
 
+	'fileName' path to the file, or `-` for standard input
+*/
+func readRepoList(fileName string) ([]string, error) {
+
+	var (
+		thisResult []string
+		thisReader io.Reader = os.Stdin
+	)
+
+	if fileName != "-" {
+		thisFile, err := os.Open(fileName)
+		if err != nil {
+			return nil, fmt.Errorf("opening %s failed. %w", fileName, err)
+		}
+		defer thisFile.Close()
+		thisReader = thisFile
 	}
 
-	thisSpinner.Stop()
+	thisScanner := bufio.NewScanner(thisReader)
+	for thisScanner.Scan() {
 
-	return allRepos, nil
+		/* Hop over blank lines and comments */
+
+		if thisLine := strings.TrimSpace(thisScanner.Text()); len(thisLine) > 0 && !strings.HasPrefix(thisLine, "#") {
+			thisResult = append(thisResult, thisLine)
+		}
+	}
+
+	if err := thisScanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s failed. %w", fileName, err)
+	}
+
+	return thisResult, nil
 }
 
 /*
-findRepos returns the slice of top-level gir repo paths
+findRepos returns the slice of top-level gir repo paths, and the slice of paths that could not be read.
+When run is over, the paths found so far are returned along with the error
 
 	'dirName' path to search and its children
-	'lookForSubGits' not implemented
+	'config' rules of the search
 */
-func findRepos(dirName string, lookForSubGits bool) ([]string, error) {
+func findRepos(ctx context.Context, dirName string, config tConfig) ([]string, []string, error) {
+
+	thisExcluder, err := newExcluder(config.excludes)
+	if err != nil {
+		return []string{}, []string{}, fmt.Errorf("findRepos: newExcluder failed. %w", err)
+	}
+
+	return searchRepos(ctx, dirName, config, thisExcluder)
+}
+
+/*
+searchRepos returns findRepos result, reading per dir exclusion files into the given excluder
+
+	'dirName' path to search and its children
+	'config' rules of the search
+	'thisExcluder' exclusion rules, to be extended with the files found
+*/
+func searchRepos(ctx context.Context, dirName string, config tConfig, thisExcluder *tExcluder) ([]string, []string, error) {
 
 	var (
-		thisResult  []string
-		thisSpinner *spinner.Spinner = nil
+		thisResult     []string
+		thisUnreadable []string                             // Skipped, as could not be read
+		thisSeen                        = map[string]bool{} // Top levels already appended
+		thisSpinner    *spinner.Spinner = nil
 	)
 
 	thisSpinner = spinner.New(spinner.CharSets[14], time.Duration(SPINNER_MS)*time.Millisecond, spinner.WithWriter(os.Stderr),
 		spinner.WithSuffix(" Finding repositories\n"))
-	thisSpinner.Start() // Starting spinner to show visual work
+	thisSpinner.Start()      // Starting spinner to show visual work
+	defer thisSpinner.Stop() // Also when interrupted
 
-	fsys := os.DirFS(dirName)
-
-	err := fs.WalkDir(
-		fsys,
-		".",
+	var err error
+	err = walkDirs(
+		dirName,
+		config,
 		func(thisPath string, thisDir fs.DirEntry, err error) error {
 
 			var thisFullPath = filepath.Join(dirName, thisPath)
 
+			/* Stop when run is over */
+
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+
 			if err != nil {
-				return fmt.Errorf("findRepos: WalkDirFunc: error accessing path %s", thisFullPath)
+
+				/* Search root must be readable */
+
+				if thisPath == "." {
+					return fmt.Errorf("searchRepos: WalkDirFunc: error accessing path %s. %w", thisFullPath, err)
+				}
+
+				/* Hop over the unreadable */
+
+				thisUnreadable = append(thisUnreadable, thisFullPath)
+				if loggingLevel >= 1 {
+					logWarning.Printf("searchRepos: skipping unreadable: %s", err)
+				}
+				// Skipping
+				return filepath.SkipDir
 			}
 
 			/* Hop over the files */
@@ -160,35 +511,124 @@ func findRepos(dirName string, lookForSubGits bool) ([]string, error) {
 				return nil
 			}
 
-			/* Hop over non-git dirs */
+			/* Hop over git's own database */
 
-			var isInGit bool
+			if thisDir.Name() == ".git" {
+				// Skipping
+				return filepath.SkipDir
+			}
+
+			/* Hop over excluded dirs */
 
-			if isInGit, err = isInGitWorkTree(thisFullPath); err != nil {
-				return fmt.Errorf("findRepos: isInGitWorkTree failed. %w", err)
+			if thisPath != "." && thisExcluder.isExcluded(thisPath) {
+				if loggingLevel >= 3 {
+					logInfo.Printf("searchRepos: excluding: %s", thisFullPath)
+				}
+				// Skipping
+				return filepath.SkipDir
 			}
 
-			if !isInGit {
+			/* Read exclusions applying beneath this dir */
+
+			thisBase := thisPath
+			if thisBase == "." {
+				thisBase = ""
+			}
+			if err = thisExcluder.addFile(filepath.Join(thisFullPath, IGNORE_FILE), thisBase); err != nil {
+				thisUnreadable = append(thisUnreadable, thisFullPath)
+				if loggingLevel >= 1 {
+					logWarning.Printf("searchRepos: skipping unreadable: %s", err)
+				}
 				// Skipping
-				return nil
+				return filepath.SkipDir
+			}
+
+			/* Take bare repos, never walking into them */
+
+			if thisPath != "." && isBareCandidate(thisFullPath) {
+				if thisBare, isBare := getBareRoot(ctx, thisFullPath, config); isBare {
+					if !thisSeen[thisBare] {
+						thisSeen[thisBare] = true
+						thisResult = append(thisResult, thisBare)
+						if loggingLevel >= 3 {
+							logInfo.Printf("searchRepos: appending bare: %s", thisBare)
+						}
+					}
+					// Skipping
+					return filepath.SkipDir
+				}
+			}
+
+			/* Hop over non-git dirs, looking at the filesystem only */
+
+			if !hasGitEntry(thisFullPath) {
+
+				/* Walk may start anywhere within a work tree */
+
+				if thisPath != "." {
+					// Skipping
+					return nil
+				}
+
+				var isInGit bool
+
+				if isInGit, err = isInGitWorkTree(ctx, thisFullPath, config); err != nil {
+					return fmt.Errorf("searchRepos: isInGitWorkTree failed. %w", err)
+				}
+
+				if !isInGit {
+					// Skipping
+					return nil
+				}
 			}
 
-			/* Get top level git path */
+			/* Confirm top level git path */
 
 			var gitTopLevel string
 
-			if gitTopLevel, err = getGitTopLevel(thisFullPath); err != nil {
-				return fmt.Errorf("findRepos: gitTopLevel failed. %w", err)
+			if gitTopLevel, err = getGitTopLevel(ctx, thisFullPath, config); err != nil {
+				if loggingLevel >= 1 {
+					logWarning.Printf("searchRepos: not a repo, despite .git found: %s", thisFullPath)
+				}
+				// Skipping
+				return nil
+			}
+
+			/* Hop over dirs of the work tree already appended */
+
+			if thisSeen[gitTopLevel] {
+				// Skipping
+				return nil
+			}
+			thisSeen[gitTopLevel] = true
+
+			/* Hop over submodules, unless asked for */
+
+			if config.lookForSubGits && !config.showSubmodules {
+
+				var thisSuperproject string
+
+				if thisSuperproject, err = getSuperproject(ctx, gitTopLevel, config); err != nil {
+					return fmt.Errorf("searchRepos: getSuperproject failed. %w", err)
+				}
+
+				if len(thisSuperproject) > 0 {
+					if loggingLevel >= 3 {
+						logInfo.Printf("searchRepos: skipping submodule: %s", gitTopLevel)
+					}
+					// Skipping
+					return filepath.SkipDir
+				}
 			}
 
 			thisResult = append(thisResult, gitTopLevel)
 			if loggingLevel >= 3 {
-				logInfo.Printf("findRepos: appending: %s", gitTopLevel)
+				logInfo.Printf("searchRepos: appending: %s", gitTopLevel)
 			}
 
 			/* Hop over every dir beneath the one */
 
-			if !lookForSubGits {
+			if !config.lookForSubGits {
 				if loggingLevel >= 3 {
 					logInfo.Println("Skipping dir")
 				}
@@ -200,24 +640,91 @@ func findRepos(dirName string, lookForSubGits bool) ([]string, error) {
 		},
 	)
 	if err != nil {
-		return []string{}, fmt.Errorf("findRepos: WalkDir failed. %w", err)
+
+		/* Keep what was found, when run is over */
+
+		if ctx.Err() != nil {
+			return thisResult, thisUnreadable, fmt.Errorf("searchRepos: walkDirs failed. %w", err)
+		}
+		return []string{}, []string{}, fmt.Errorf("searchRepos: walkDirs failed. %w", err)
 	}
 
 	if loggingLevel >= 2 {
-		logInfo.Printf("findRepos: thisResult: %+v\n", thisResult)
+		logInfo.Printf("searchRepos: thisResult: %+v\n", thisResult)
+		logInfo.Printf("searchRepos: thisUnreadable: %+v\n", thisUnreadable)
 	}
 
-	thisSpinner.Stop()
+	return thisResult, thisUnreadable, nil
+}
 
-	return thisResult, nil
+/*
+hasGitEntry returns if path holds a .git directory or a .git file pointing to the git directory
+
+	'dirName' path to be checked
+*/
+func hasGitEntry(dirName string) bool {
+
+	thisEntry, err := os.Lstat(filepath.Join(dirName, ".git"))
+	if err != nil {
+		return false
+	}
+
+	return thisEntry.IsDir() || thisEntry.Mode().IsRegular()
+}
+
+/*
+isBareCandidate returns if path looks like a bare repo, looking at the filesystem only
+
+	'dirName' path to be checked
+*/
+func isBareCandidate(dirName string) bool {
+
+	if thisHead, err := os.Stat(filepath.Join(dirName, "HEAD")); err != nil || !thisHead.Mode().IsRegular() {
+		return false
+	}
+
+	for _, thisSub := range []string{"objects", "refs"} {
+		if thisDir, err := os.Stat(filepath.Join(dirName, thisSub)); err != nil || !thisDir.IsDir() {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+getBareRoot returns absolute path of the bare repo, and if path is one
+
+	'dirName' path to be checked
+	'config' rules of the command
+*/
+func getBareRoot(ctx context.Context, dirName string, config tConfig) (string, bool) {
+
+	commCommand := "git"
+	argsBare := append([]string{},
+		"rev-parse", "--is-bare-repository", "--absolute-git-dir",
+	)
+
+	cmdOutput, err := runCommand(ctx, config, commCommand, argsBare, dirName)
+	if err != nil {
+		return "", false
+	}
+
+	thisLines := strings.Split(cmdOutput, "\n")
+	if len(thisLines) != 2 || thisLines[0] != "true" {
+		return "", false
+	}
+
+	return thisLines[1], true
 }
 
 /*
 isInGitWorkTree returns if path is within git work tree
 
 	'dirName' path to be checked
+	'config' rules of the command
 */
-func isInGitWorkTree(dirName string) (bool, error) {
+func isInGitWorkTree(ctx context.Context, dirName string, config tConfig) (bool, error) {
 
 	/* Get os object */
 	if loggingLevel >= 3 {
@@ -238,8 +745,12 @@ func isInGitWorkTree(dirName string) (bool, error) {
 
 	/* Execute command */
 
-	cmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
+	ctx, cancel := getCommandContext(ctx, config)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--is-inside-work-tree")
 	cmd.Dir = dirName
+	cmd.Env = getCommandEnv()
 	out_bytes, err := cmd.Output()
 	if err != nil {
 		return false, nil // Not in git work tree anyway
@@ -253,8 +764,9 @@ func isInGitWorkTree(dirName string) (bool, error) {
 getGitTopLevel returns top-level path of git repo
 
 	'dirName' path to repo or any of its child directories
+	'config' rules of the command
 */
-func getGitTopLevel(dirName string) (string, error) {
+func getGitTopLevel(ctx context.Context, dirName string, config tConfig) (string, error) {
 
 	dir, err := os.Stat(dirName)
 	if err != nil {
@@ -265,8 +777,12 @@ func getGitTopLevel(dirName string) (string, error) {
 
 		/* Execute command */
 
-		cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+		ctx, cancel := getCommandContext(ctx, config)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, "git", "rev-parse", "--show-toplevel")
 		cmd.Dir = dirName
+		cmd.Env = getCommandEnv()
 		out_bytes, err := cmd.Output()
 		if err != nil {
 			return "", fmt.Errorf("getting cmd output failed. %w", err)
@@ -277,3 +793,43 @@ func getGitTopLevel(dirName string) (string, error) {
 
 	return "", errors.New("not a directory")
 }
+
+/*
+getSuperproject returns top-level path of the superproject, when repo is its submodule
+
+	'dirName' path to repo or any of its child directories
+	'config' rules of the command
+*/
+func getSuperproject(ctx context.Context, dirName string, config tConfig) (string, error) {
+
+	commCommand := "git"
+	argsSuperproject := append([]string{},
+		"rev-parse", "--show-superproject-working-tree",
+	)
+
+	cmdOutput, err := runCommand(ctx, config, commCommand, argsSuperproject, dirName)
+	if err != nil {
+		return "", fmt.Errorf("getting superproject output failed. %w", err)
+	}
+
+	return cmdOutput, nil
+}
+
+/*
+reportUnreadable emits count of the paths that could not be read, and lists them when asked for
+
+	'unreadable' paths skipped by findRepos
+	'config' rules of the report
+*/
+func reportUnreadable(unreadable []string, config tConfig) {
+
+	if len(unreadable) == 0 {
+		return
+	}
+
+	if config.listUnreadable {
+		logWarning.Printf("%d unreadable paths skipped:\n%s", len(unreadable), strings.Join(unreadable, "\n"))
+	} else if loggingLevel >= 1 {
+		logWarning.Printf("%d unreadable paths skipped.", len(unreadable))
+	}
+}
@@ -1,8 +1,11 @@
 package cmd
 
 const (
-	MAX_LOGGING_LEVEL int    = 3            // Maximum allowed logging level
-	SHELL             string = "bash"       // Name of shell to be invoked for 'shell' command
-	SPINNER_MS        int    = 500          // Spinner refresh period in miliseconds
-	UP_TO_DATE        string = "up to date" // Emitted when local repo is in sync with remote one
+	MAX_LOGGING_LEVEL     int    = 3              // Maximum allowed logging level
+	SHELL                 string = "bash"         // Name of shell to be invoked for 'shell' command
+	SPINNER_MS            int    = 500            // Spinner refresh period in miliseconds
+	WAIT_DELAY_MS         int    = 1000           // Time given to command's children to release output, in miliseconds
+	IGNORE_FILE           string = ".gitasignore" // Holds exclusions in gitignore syntax
+	SHORT_OID_LEN         int    = 7              // Length of abbreviated commit id
+	INTERRUPTED_EXIT_CODE int    = 130            // Exit code when interrupted, as shells do for SIGINT
 )
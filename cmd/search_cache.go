@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type tCache struct {
+	Root       string               `json:"root"`       // Full path searched
+	Options    string               `json:"options"`    // Search rules the repos were found with
+	Created    time.Time            `json:"created"`    // For expiry purposes
+	Repos      []string             `json:"repos"`      // Top-level paths found
+	Unreadable []string             `json:"unreadable"` // Paths that could not be read
+	RuleFiles  map[string]time.Time `json:"ruleFiles"`  // Exclusion files read, with their modification times, zero when missing
+}
+
+/*
+findReposCached returns findRepos result, reusing the one cached on disk when still valid
+
+	'dirName' path to search and its children
+	'config' rules of the search and of the cache
+*/
+func findReposCached(ctx context.Context, dirName string, config tConfig) ([]string, []string, error) {
+
+	if config.cacheTTL <= 0 {
+		return findRepos(ctx, dirName, config)
+	}
+
+	thisCacheFile, thisCache, err := getCache(dirName, config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting cache failed. %w", err)
+	}
+
+	/* Reuse the cache */
+
+	if !config.rescan && isCacheValid(thisCache, config) {
+		if loggingLevel >= 2 {
+			logInfo.Printf("findReposCached: %d repos taken from %s", len(thisCache.Repos), thisCacheFile)
+		}
+		return thisCache.Repos, thisCache.Unreadable, nil
+	}
+
+	/* Rescan and refresh the cache */
+
+	thisExcluder, err := newExcluder(config.excludes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("newExcluder failed. %w", err)
+	}
+
+	thisCache.Repos, thisCache.Unreadable, err = searchRepos(ctx, dirName, config, thisExcluder)
+	if err != nil {
+		return thisCache.Repos, thisCache.Unreadable, err // Partial, never cached
+	}
+	thisCache.Created = time.Now()
+
+	/* Root's exclusion file is watched even when missing, as the one most likely added */
+
+	thisCache.RuleFiles = map[string]time.Time{filepath.Join(thisCache.Root, IGNORE_FILE): {}}
+	for thisFile, thisModTime := range thisExcluder.files {
+		if thisAbs, err := filepath.Abs(thisFile); err == nil {
+			thisCache.RuleFiles[thisAbs] = thisModTime
+		}
+	}
+
+	if err := writeCache(thisCacheFile, thisCache); err != nil {
+		logWarning.Printf("writing cache failed. %s", err)
+	}
+
+	return thisCache.Repos, thisCache.Unreadable, nil
+}
+
+/*
+getCache returns path of the cache file and its content, empty when not cached yet
+
+	'dirName' path to search and its children
+	'config' rules of the search
+*/
+func getCache(dirName string, config tConfig) (string, tCache, error) {
+
+	var thisCache tCache
+
+	thisRoot, err := filepath.Abs(dirName)
+	if err != nil {
+		return "", thisCache, fmt.Errorf("getting absolute path failed. %w", err)
+	}
+
+	thisCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", thisCache, fmt.Errorf("getting cache dir failed. %w", err)
+	}
+
+	/* Same root searched with other rules gets another file */
+
+	thisOptions := fmt.Sprintf("nested=%t submodules=%t excludes=%q rules=%s depth=%d symlinks=%t",
+		config.lookForSubGits, config.showSubmodules, config.excludes, getUserRulesHash(), config.maxDepth, config.followSymlinks)
+
+	thisKey := sha256.Sum256([]byte(thisRoot + "\x00" + thisOptions))
+	thisCacheFile := filepath.Join(thisCacheDir, "gitas", "repos-"+hex.EncodeToString(thisKey[:8])+".json")
+
+	thisCache.Root = thisRoot
+	thisCache.Options = thisOptions
+
+	thisBytes, err := os.ReadFile(thisCacheFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return thisCacheFile, thisCache, nil
+		}
+		return "", thisCache, fmt.Errorf("reading %s failed. %w", thisCacheFile, err)
+	}
+
+	/* Broken cache is as good as none */
+
+	var thisRead tCache
+
+	if err := json.Unmarshal(thisBytes, &thisRead); err != nil {
+		if loggingLevel >= 1 {
+			logWarning.Printf("ignoring broken cache %s. %s", thisCacheFile, err)
+		}
+		return thisCacheFile, thisCache, nil
+	}
+
+	return thisCacheFile, thisRead, nil
+}
+
+/*
+getUserRulesHash returns short hash of the user's exclusion file, `none` when missing
+*/
+func getUserRulesHash() string {
+
+	thisBytes, err := os.ReadFile(getUserIgnoreFile())
+	if err != nil {
+		return "none"
+	}
+
+	thisHash := sha256.Sum256(thisBytes)
+
+	return hex.EncodeToString(thisHash[:8])
+}
+
+/*
+isCacheValid returns if cache is fresh, its exclusion files unchanged, and every cached path still holds a repo
+
+	'thisCache' content of the cache
+	'config' rules of the cache
+*/
+func isCacheValid(thisCache tCache, config tConfig) bool {
+
+	if thisCache.Created.IsZero() || time.Since(thisCache.Created) > config.cacheTTL {
+		return false
+	}
+
+	/* Edited or removed exclusion file may change what is found */
+
+	for thisFile, thisModTime := range thisCache.RuleFiles {
+		thisInfo, err := os.Stat(thisFile)
+		if thisModTime.IsZero() && os.IsNotExist(err) {
+			continue // Still missing
+		}
+		if err != nil || !thisInfo.ModTime().Equal(thisModTime) {
+			if loggingLevel >= 2 {
+				logInfo.Printf("isCacheValid: exclusion file changed: %s", thisFile)
+			}
+			return false
+		}
+	}
+
+	for _, thisRepo := range thisCache.Repos {
+		if !hasGitEntry(thisRepo) && !isBareCandidate(thisRepo) {
+			if loggingLevel >= 2 {
+				logInfo.Printf("isCacheValid: not a repo anymore: %s", thisRepo)
+			}
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+writeCache stores the cache on disk
+
+	'cacheFile' path to the file
+	'thisCache' content to be stored
+*/
+func writeCache(cacheFile string, thisCache tCache) error {
+
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0o755); err != nil {
+		return fmt.Errorf("creating cache dir failed. %w", err)
+	}
+
+	thisBytes, err := json.MarshalIndent(thisCache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling cache failed. %w", err)
+	}
+
+	/* Rename makes concurrent runs see either old or new cache */
+
+	thisTemp := cacheFile + fmt.Sprintf(".%d.tmp", os.Getpid())
+	if err := os.WriteFile(thisTemp, thisBytes, 0o644); err != nil {
+		return fmt.Errorf("writing %s failed. %w", thisTemp, err)
+	}
+
+	if err := os.Rename(thisTemp, cacheFile); err != nil {
+		return fmt.Errorf("renaming %s failed. %w", thisTemp, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+/*
+initSearchFlags sets the flags driving findRepos, shared by every command that searches for repos
+
+	'thisCmd' command to be given the flags
+*/
+func initSearchFlags(thisCmd *cobra.Command) {
+	thisCmd.Flags().BoolVar(&config.lookForSubGits, "nested", false, "repos nested in other repos searched")
+	thisCmd.Flags().BoolVar(&config.showSubmodules, "submodules", false, "submodules searched and shown (implies --nested)")
+	thisCmd.Flags().StringArrayVar(&config.excludes, "exclude", []string{}, "`glob` of dirs not searched, repeatable")
+	thisCmd.Flags().IntVar(&config.maxDepth, "max-depth", -1, "maximum depth of search, negative for unlimited")
+	thisCmd.Flags().BoolVar(&config.followSymlinks, "follow-symlinks", false, "symlinked dirs searched")
+	thisCmd.Flags().BoolVar(&config.listUnreadable, "unreadable", false, "unreadable dirs listed at the end")
+	thisCmd.Flags().StringVar(&config.fromFile, "from-file", "", "repos listed in `FILE`, one per line, - for stdin")
+	thisCmd.Flags().DurationVar(&config.cacheTTL, "cache-ttl", 0, "search results reused for `duration`, e.g. 1h (default none)")
+	thisCmd.Flags().BoolVar(&config.rescan, "rescan", false, "cached search results refreshed")
+	thisCmd.Flags().DurationVar(&config.gitTimeout, "timeout", 0, "time allowed for a single git command, e.g. 10s (default none)")
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type tExcludeRule struct {
+	base     string // Slash separated dir, relative to the search root, the rule applies to
+	pattern  string // Glob, with `**` matching any number of segments
+	negate   bool   // Re-includes, when pattern started with `!`
+	anchored bool   // Matched against the whole path, not just its last segment
+}
+
+type tExcluder struct {
+	rules []tExcludeRule       // Later rules take precedence
+	files map[string]time.Time // Rule files read, with their modification times
+}
+
+/*
+newExcluder returns excluder built of the user's config file and 'excludes' globs
+
+	'excludes' globs given with --exclude flags
+*/
+func newExcluder(excludes []string) (*tExcluder, error) {
+
+	thisExcluder := new(tExcluder)
+
+	/* Rules from user config directory */
+
+	if thisUserFile := getUserIgnoreFile(); len(thisUserFile) > 0 {
+		if err := thisExcluder.addFile(thisUserFile, ""); err != nil {
+			return nil, fmt.Errorf("reading user's %s failed. %w", IGNORE_FILE, err)
+		}
+	}
+
+	/* Rules from flags take precedence */
+
+	for _, thisExclude := range excludes {
+		thisExcluder.addLine(thisExclude, "")
+	}
+
+	return thisExcluder, nil
+}
+
+/*
+getUserIgnoreFile returns path of the user's exclusion file, empty when there is no user config directory
+*/
+func getUserIgnoreFile() string {
+
+	thisConfigDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(thisConfigDir, "gitas", IGNORE_FILE)
+}
+
+/*
+addFile appends rules of the gitignore-like file, missing file is ignored
+
+	'fileName' path to the file
+	'base' slash separated dir, relative to the search root, the file applies to
+*/
+func (e *tExcluder) addFile(fileName string, base string) error {
+
+	thisFile, err := os.Open(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("opening %s failed. %w", fileName, err)
+	}
+	defer thisFile.Close()
+
+	/* Remember the file, so its edits invalidate the cache */
+
+	thisInfo, err := thisFile.Stat()
+	if err != nil {
+		return fmt.Errorf("getting info of %s failed. %w", fileName, err)
+	}
+	if e.files == nil {
+		e.files = map[string]time.Time{}
+	}
+	e.files[fileName] = thisInfo.ModTime()
+
+	thisScanner := bufio.NewScanner(thisFile)
+	for thisScanner.Scan() {
+		e.addLine(thisScanner.Text(), base)
+	}
+
+	if err := thisScanner.Err(); err != nil {
+		return fmt.Errorf("reading %s failed. %w", fileName, err)
+	}
+
+	if loggingLevel >= 2 {
+		logInfo.Printf("exclude rules read from %s", fileName)
+	}
+
+	return nil
+}
+
+/*
+addLine appends a single rule written in gitignore syntax
+
+	'line' the rule
+	'base' slash separated dir, relative to the search root, the rule applies to
+*/
+func (e *tExcluder) addLine(line string, base string) {
+
+	var thisRule = tExcludeRule{base: base}
+
+	line = strings.TrimRight(line, " \t\r")
+
+	/* Hop over blanks and comments */
+
+	if len(line) == 0 || strings.HasPrefix(line, "#") {
+		return
+	}
+
+	if strings.HasPrefix(line, "!") {
+		thisRule.negate = true
+		line = line[1:]
+	}
+
+	/* Only dirs are ever matched, so trailing slash is meaningless */
+
+	line = strings.TrimRight(line, "/")
+
+	/* Slash anywhere but at the end anchors the pattern */
+
+	if strings.Contains(line, "/") {
+		thisRule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+
+	if len(line) == 0 {
+		return
+	}
+
+	thisRule.pattern = line
+	e.rules = append(e.rules, thisRule)
+}
+
+/*
+isExcluded returns if dir should be hopped over
+
+	'relPath' slash separated dir, relative to the search root
+*/
+func (e *tExcluder) isExcluded(relPath string) bool {
+
+	var thisExcluded bool
+
+	for _, thisRule := range e.rules {
+
+		/* Rule applies to dirs beneath its base only */
+
+		thisPath := relPath
+		if len(thisRule.base) > 0 {
+			if !strings.HasPrefix(relPath, thisRule.base+"/") {
+				continue
+			}
+			thisPath = strings.TrimPrefix(relPath, thisRule.base+"/")
+		}
+
+		if !thisRule.anchored {
+			thisPath = path.Base(thisPath)
+		}
+
+		if matchGlob(thisRule.pattern, thisPath) {
+			thisExcluded = !thisRule.negate
+		}
+	}
+
+	return thisExcluded
+}
+
+/*
+matchGlob returns if slash separated 'name' matches 'pattern', where `**` matches any number of segments
+
+	'pattern' glob to be matched
+	'name' path to be checked
+*/
+func matchGlob(pattern string, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+/*
+matchSegments is recursive part of matchGlob
+*/
+func matchSegments(patterns []string, names []string) bool {
+
+	if len(patterns) == 0 {
+		return len(names) == 0
+	}
+
+	if patterns[0] == "**" {
+		for i := 0; i <= len(names); i++ {
+			if matchSegments(patterns[1:], names[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(names) == 0 {
+		return false
+	}
+
+	if isMatched, err := path.Match(patterns[0], names[0]); err != nil || !isMatched {
+		return false
+	}
+
+	return matchSegments(patterns[1:], names[1:])
+}
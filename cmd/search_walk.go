@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+/*
+tLinkEntry is the entry of a symlink's target, named as the symlink
+*/
+type tLinkEntry struct {
+	fs.DirEntry
+	name string
+}
+
+/*
+Name returns the symlink's name
+*/
+func (e tLinkEntry) Name() string {
+	return e.name
+}
+
+/*
+walkDirs walks the tree like fs.WalkDir, but limits the depth and follows symlinks when asked for
+
+	'dirName' root of the tree
+	'config' rules of the walk
+	'visit' called for every entry, with slash separated path relative to the root
+*/
+func walkDirs(dirName string, config tConfig, visit fs.WalkDirFunc) error {
+
+	var thisVisited = map[string]bool{} // Dirs already walked, by device and inode
+
+	thisInfo, err := os.Stat(dirName)
+	if err != nil {
+		return visit(".", nil, err)
+	}
+
+	if config.followSymlinks {
+		thisVisited[getDirKey(dirName, thisInfo)] = true
+	}
+
+	err = visit(".", fs.FileInfoToDirEntry(thisInfo), nil)
+	if err != nil || !thisInfo.IsDir() {
+		if errors.Is(err, filepath.SkipDir) {
+			return nil
+		}
+		return err
+	}
+
+	var walk func(thisPath string, thisDir fs.DirEntry, thisDepth int) error
+
+	walk = func(thisPath string, thisDir fs.DirEntry, thisDepth int) error {
+
+		var thisFullPath = filepath.Join(dirName, thisPath)
+
+		thisEntries, err := os.ReadDir(thisFullPath)
+		if err != nil {
+			if err = visit(thisPath, thisDir, err); err != nil {
+				if errors.Is(err, filepath.SkipDir) {
+					return nil
+				}
+				return err
+			}
+		}
+
+		for _, thisEntry := range thisEntries {
+
+			var (
+				thisChildPath     = path.Join(thisPath, thisEntry.Name())
+				thisChildFullPath = filepath.Join(dirName, thisChildPath)
+			)
+
+			/* Replace symlink with its target, keeping the link's name */
+
+			if config.followSymlinks && thisEntry.Type()&fs.ModeSymlink != 0 {
+				if thisTarget, err := os.Stat(thisChildFullPath); err == nil && thisTarget.IsDir() {
+					thisEntry = tLinkEntry{fs.FileInfoToDirEntry(thisTarget), thisEntry.Name()}
+				}
+			}
+
+			/* Files are just visited */
+
+			if !thisEntry.IsDir() {
+				if err := visit(thisChildPath, thisEntry, nil); err != nil {
+					if errors.Is(err, filepath.SkipDir) {
+						return nil // Skipping rest of the dir
+					}
+					return err
+				}
+				continue
+			}
+
+			/* Hop over dirs too deep */
+
+			if config.maxDepth >= 0 && thisDepth+1 > config.maxDepth {
+				if loggingLevel >= 3 {
+					logInfo.Printf("walkDirs: too deep: %s", thisChildFullPath)
+				}
+				// Skipping
+				continue
+			}
+
+			/* Hop over dirs already walked, symlinks may form cycles */
+
+			if config.followSymlinks {
+				thisChildInfo, err := thisEntry.Info()
+				if err == nil {
+					thisKey := getDirKey(thisChildFullPath, thisChildInfo)
+					if thisVisited[thisKey] {
+						if loggingLevel >= 3 {
+							logInfo.Printf("walkDirs: already walked: %s", thisChildFullPath)
+						}
+						// Skipping
+						continue
+					}
+					thisVisited[thisKey] = true
+				}
+			}
+
+			if err := visit(thisChildPath, thisEntry, nil); err != nil {
+				if errors.Is(err, filepath.SkipDir) {
+					continue
+				}
+				return err
+			}
+
+			if err := walk(thisChildPath, thisEntry, thisDepth+1); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return walk(".", fs.FileInfoToDirEntry(thisInfo), 0)
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"syscall"
+)
+
+/*
+getDirKey returns identity of the dir, that is same for all paths leading to it
+
+	'dirName' path to the dir
+	'info' its stat
+*/
+func getDirKey(dirName string, info fs.FileInfo) string {
+
+	if thisStat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return fmt.Sprintf("%d:%d", thisStat.Dev, thisStat.Ino)
+	}
+
+	return dirName
+}
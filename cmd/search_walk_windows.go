@@ -0,0 +1,25 @@
+//go:build windows
+
+package cmd
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+/*
+getDirKey returns identity of the dir, that is same for all paths leading to it
+
+	'dirName' path to the dir
+	'info' its stat
+*/
+func getDirKey(dirName string, _ fs.FileInfo) string {
+
+	/* No inodes, falling back to the resolved path */
+
+	if thisReal, err := filepath.EvalSymlinks(dirName); err == nil {
+		return thisReal
+	}
+
+	return dirName
+}
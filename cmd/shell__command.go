@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,13 +11,13 @@ import (
 
 // shellCmd represents the shell command
 var shellCmd = &cobra.Command{
-	Use:   "shell [PATH] \"command\"",
+	Use:   "shell [PATH...] \"command\"",
 	Short: "Execute command",
-	Long:  `Execute "command" for each git repository found in PATH`,
+	Long:  `Execute "command" for each git repository found in every PATH`,
 
-	Example: "gitas shell /home \"ls\"\ngitas shell ~ \"git describe --abbrev=0 --tags\"\ngitas shell \"ls | grep 'P'\"",
+	Example: "gitas shell /home \"ls\"\ngitas shell ~ \"git describe --abbrev=0 --tags\"\ngitas shell \"ls | grep 'P'\"\ngitas shell --dirty-only \"git diff --stat\"",
 
-	Args: cobra.RangeArgs(1, 2),
+	Args: cobra.MinimumNArgs(1),
 
 	Run: func(cmd *cobra.Command, args []string) {
 		shellMain(args)
@@ -26,6 +27,12 @@ var shellCmd = &cobra.Command{
 // Cobra initiation
 func init() {
 	rootCmd.AddCommand(shellCmd)
+
+	/* Init flags */
+
+	shellCmd.Flags().SortFlags = false
+	initSearchFlags(shellCmd)
+	initFilterFlags(shellCmd)
 }
 
 /*
@@ -35,32 +42,44 @@ Shell main function
 */
 func shellMain(args []string) {
 	var (
-		cmdArgs  []string // Args of thecommand to execute
-		givenDir string
-		err      error
+		cmdArgs   []string // Args of thecommand to execute
+		givenDirs []string
+		err       error
 	)
 
 	checkLogginglevel(args)
 
-	/* Construct arguments */
+	/* Construct arguments, command is the last one */
+
+	givenDirs = args[:len(args)-1]
+	cmdArgs = append([]string{"-c"}, args[len(args)-1])
+
+	/* Default the PATH, unless repos are listed */
 
-	switch lenArgs := len(args); lenArgs {
-	case 1:
-		givenDir = "."
-		cmdArgs = append([]string{"-c"}, args[0])
-	case 2:
-		givenDir = args[0]
-		cmdArgs = append([]string{"-c"}, args[1])
+	if len(givenDirs) == 0 && len(config.fromFile) == 0 {
+		givenDirs = []string{"."}
 	}
 
-	/* Find repos */
+	/* Walk below found repos when looking for submodules */
 
-	git_slice, err := findRepos(givenDir, config.lookForSubGits)
-	if err != nil {
-		logError.Fatalln(fmt.Errorf("finding repos failed. %w", err))
+	if config.showSubmodules {
+		config.lookForSubGits = true
 	}
-	if loggingLevel >= 2 {
-		logInfo.Printf("%d repos found.", len(git_slice))
+
+	/* Find repos, querying their status only when filtered */
+
+	var (
+		git_slice  []string
+		unreadable []string
+	)
+
+	if isFiltered(config) {
+		git_slice, unreadable = getFilteredSlice(givenDirs)
+	} else {
+		git_slice, unreadable, err = getGitsSlice(context.Background(), givenDirs, config)
+		if err != nil {
+			logError.Fatalln(fmt.Errorf("getting repos slice failed. %w", err))
+		}
 	}
 
 	/* Execute for each repo */
@@ -69,6 +88,34 @@ func shellMain(args []string) {
 		execShell(thisGit, cmdArgs)
 	}
 
+	reportUnreadable(unreadable, config)
+
+}
+
+/*
+getFilteredSlice returns paths of repos matching the filters, and unreadable paths
+
+	'givenDirs' root paths of the search
+*/
+func getFilteredSlice(givenDirs []string) ([]string, []string) {
+
+	var thisResult []string
+
+	conditions, err := enableFilters(&config)
+	if err != nil {
+		logError.Fatalln(fmt.Errorf("checking filters failed. %w", err))
+	}
+
+	repos, unreadable, err := getReposDictionary(context.Background(), givenDirs, config)
+	if err != nil {
+		logError.Fatalln(fmt.Errorf("getting repos dictionary failed. %w", err))
+	}
+
+	for _, thisRepo := range filterRepos(repos, config, conditions) {
+		thisResult = append(thisResult, thisRepo.TopLevelPath)
+	}
+
+	return thisResult, unreadable
 }
 
 /*
@@ -1,22 +1,27 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"sort"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/template"
 
 	"github.com/spf13/cobra"
 )
 
 // statusCmd represents the status command
 var statusCmd = &cobra.Command{
-	Use:   "status [PATH]",
+	Use:   "status [PATH...]",
 	Short: "Show status",
-	Long:  `Show status of each git repository found in PATH`,
+	Long:  `Show status of each git repository found in every PATH`,
 
-	Example: "gitas status ~ --name=p -b=true -o=n\ngitas status -lus\ngitas status /home --time=false",
+	Example: "gitas status ~ --name=p -b=true -o=n\ngitas status -lus\ngitas status /home --time=false\ngitas status ~/src ~/work --from-file repos.txt",
 	Aliases: []string{"ll"},
 
-	Args: cobra.MaximumNArgs(1),
+	Args: cobra.ArbitraryArgs,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		statusMain(args)
@@ -39,42 +44,79 @@ func init() {
 	statusCmd.Flags().VarP(config.nameShown, "name", "n", "name shown: unique|path|short") // Choice
 
 	statusCmd.Flags().BoolVarP(&config.showCommitTime, "time", "t", true, "time of last commit shown")
-	statusCmd.Flags().VarP(config.timeFormat, "format", "f", "format time: relative|iso") // Choice
+	statusCmd.Flags().VarP(config.timeFormat, "format", "f", "format time: relative|iso")   // Choice
+	statusCmd.Flags().Var(config.dateKind, "date", "date of last commit: committer|author") // Choice
+	statusCmd.Flags().BoolVar(&config.showCommitHash, "hash", false, "short hash of last commit shown")
+	statusCmd.Flags().BoolVar(&config.showCommitAuthor, "author", false, "author of last commit shown")
+	statusCmd.Flags().BoolVar(&config.showCommitSubject, "subject", false, "subject of last commit shown")
 
 	statusCmd.Flags().BoolVarP(&config.showBranchHead, "branch", "b", false, "branch shown")
 	statusCmd.Flags().BoolVarP(&config.showFetchNeeded, "query", "q", false, "query fetch needed (implies -br)")
 	statusCmd.Flags().BoolVarP(&config.showBranchUpstream, "remote", "r", false, "remote shown")
+	statusCmd.Flags().BoolVar(&config.showDefaultAB, "default", false, "ahead / behind default branch shown")
 
 	statusCmd.Flags().BoolVarP(&config.showUrl, "url", "l", false, "url shown")
 
 	statusCmd.Flags().BoolVarP(&config.showDirty, "dirty", "d", true, "dirty shown")
 	statusCmd.Flags().BoolVarP(&config.showUntracked, "untracked", "u", false, "untracked shown")
+	statusCmd.Flags().BoolVarP(&config.showCounts, "counts", "c", false, "file counts by kind of change shown")
 	statusCmd.Flags().BoolVarP(&config.showStash, "stash", "s", false, "stash shown")
-
-	statusCmd.Flags().VarP(config.sortOrder, "order", "o", "order: time|name")                 // Choice
-	statusCmd.Flags().VarP(config.emitFormat, "emit", "e", "emit format: table|json|markdown") // Choice
+	statusCmd.Flags().BoolVar(&config.showStashAge, "stash-age", false, "time of oldest stash shown (implies -s)")
+
+	statusCmd.Flags().VarP(config.sortOrder, "order", "o", "order: time|name") // Choice
+	statusCmd.Flags().StringSliceVar(&config.sortKeys, "sort", nil, "sort by columns, - prefixed when descending, e.g. -dirty,group,name (overrides -o)")
+	statusCmd.Flags().BoolVarP(&config.groupWorktrees, "worktrees", "w", false, "worktrees grouped under main repo")
+	statusCmd.Flags().VarP(config.emitFormat, "emit", "e", "emit format: table|json|markdown|csv|tsv (separated by tab)|pattern (template)") // Choice
+	statusCmd.Flags().StringSliceVar(&config.columns, "columns", nil, "columns shown in order, e.g. name,branch,ab,dirty,url (overrides flags)")
+	statusCmd.Flags().StringVar(&config.template, "template", "", "go `template` rendering repos, e.g. '{{.UniqueName}} {{.BranchHead}}' (implies -e p)")
+	statusCmd.Flags().StringVar(&config.templateFile, "template-file", "", "go template read from `FILE` (implies -e p)")
+	statusCmd.Flags().Var(config.templateScope, "template-scope", "template renders: repo, each on its line|all, given the slice") // Choice
+
+	statusCmd.Flags().IntVarP(&config.jobs, "jobs", "j", 0, "number of repos queried concurrently (default CPU count)")
+	statusCmd.Flags().BoolVar(&config.failOnError, "fail", false, "exit with non-zero code when any repo failed")
+	statusCmd.Flags().DurationVar(&config.deadline, "deadline", 0, "time allowed for the whole run, e.g. 1m (default none)")
+
+	initSearchFlags(statusCmd)
+	initFilterFlags(statusCmd)
 }
 
 /*
 Main status function
 
-	'args' given command line arguments, that contain the root os search path
+	'args' given command line arguments, that contain the root os search paths
 */
 func statusMain(args []string) {
 
-	var givenDir string
+	var givenDirs = args
 
 	checkLogginglevel(args)
 
-	/* Default the PATH */
+	/* Default the PATH, unless repos are listed */
 
-	if len(args) != 1 {
+	if len(givenDirs) == 0 && len(config.fromFile) == 0 {
 		if loggingLevel >= 1 {
 			logWarning.Println("Defaulting PATH")
 		}
-		givenDir = "."
-	} else {
-		givenDir = args[0]
+		givenDirs = []string{"."}
+	}
+
+	/* Retrieve data of listed, sorted by and filtered by columns */
+
+	if err := enableColumns(&config); err != nil {
+		logError.Fatalln(fmt.Errorf("checking columns failed. %w", err))
+	}
+	if err := enableSortKeys(&config); err != nil {
+		logError.Fatalln(fmt.Errorf("checking sort keys failed. %w", err))
+	}
+	conditions, err := enableFilters(&config)
+	if err != nil {
+		logError.Fatalln(fmt.Errorf("checking filters failed. %w", err))
+	}
+
+	/* Walk below found repos when looking for submodules */
+
+	if config.showSubmodules {
+		config.lookForSubGits = true
 	}
 
 	/* Show branch infos when querying sync need */
@@ -84,41 +126,138 @@ func statusMain(args []string) {
 		config.showBranchUpstream = true
 	}
 
+	/* Show stash when showing its age */
+
+	if config.showStashAge {
+		config.showStash = true
+	}
+
+	/* Emit with template when given, querying all local data */
+
+	var thisTemplate *template.Template
+
+	if len(config.template) > 0 || len(config.templateFile) > 0 {
+		config.emitFormat.Value = "p"
+	}
+
+	if config.emitFormat.Value == "p" {
+		var err error
+		if thisTemplate, err = getTemplate(config); err != nil {
+			logError.Fatalln(fmt.Errorf("getting template failed. %w", err))
+		}
+		config.showUrl = true
+		config.showCommitTime = true
+		config.showCommitHash = true
+		config.showCommitAuthor = true
+		config.showCommitSubject = true
+		config.showBranchHead = true
+		config.showBranchUpstream = true
+		config.showDefaultAB = true
+		config.showDirty = true
+		config.showUntracked = true
+		config.showCounts = true
+		config.showStash = true
+		config.showStashAge = true
+		config.showParent = true
+	}
+
 	/* Query all data when emitting json */
 
 	if config.emitFormat.Value == "j" {
 		config.showUrl = true
 		config.showCommitTime = true
+		config.showCommitHash = true
+		config.showCommitAuthor = true
+		config.showCommitSubject = true
 		config.showBranchHead = true
 		config.showBranchUpstream = true
+		config.showDefaultAB = true
 		config.showDirty = true
 		config.showUntracked = true
+		config.showCounts = true
 		config.showStash = true
+		config.showStashAge = true
+		config.showParent = true
 		config.timeFormat.Value = "I"
 		config.showFetchNeeded = true
 	}
 
-	/* Get repos under 'givenDir' */
+	/* Interrupt cancels git commands in flight, what is gathered so far gets emitted */
 
-	repos, err := getReposDictionary(givenDir, config)
-	if err != nil {
-		logError.Fatalln(fmt.Errorf("getting repos dictionary failed. %w", err))
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-sigCtx.Done()
+		stop() // Next interrupt kills at once
+	}()
+
+	ctx := sigCtx
+
+	/* Limit the whole run */
+
+	if config.deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.deadline)
+		defer cancel()
+	}
+
+	/* Get repos under 'givenDirs' */
+
+	repos, unreadable, searchErr := getReposDictionary(ctx, givenDirs, config)
+	if searchErr != nil {
+
+		/* Run is over while searching, what was found is still reported */
+
+		if ctx.Err() == nil {
+			logError.Fatalln(fmt.Errorf("getting repos dictionary failed. %w", searchErr))
+		}
+		if errors.Is(searchErr, context.Canceled) {
+			logWarning.Println("interrupted while finding repos, list incomplete.")
+		} else {
+			logWarning.Println("deadline reached while finding repos, list incomplete.")
+		}
 	}
 	if loggingLevel >= 3 {
 		logInfo.Printf("repos: %+v", repos)
 	}
 
+	/* Failures are summarized, even when filtered out */
+
+	failedCount := countFailed(repos)
+	incompleteCount := countIncomplete(repos)
+	totalCount := len(repos)
+
+	if isFiltered(config) {
+		repos = filterRepos(repos, config, conditions)
+	}
+
+	/* Show error marker only when needed */
+
+	config.showError = countFailed(repos)+countIncomplete(repos) > 0
+
+	/* Show layout marker and operation only when needed */
+
+	for _, thisRepo := range repos {
+		if thisRepo.Bare || len(thisRepo.MainRepo) > 0 {
+			config.showLayout = true
+		}
+		if len(thisRepo.Operation) > 0 {
+			config.showOperation = true
+		}
+	}
+
 	/* Sort repositories */
 
-	if config.sortOrder.Value == "n" {
-		sort.SliceStable(repos, func(i, j int) bool {
-			return repos[i].UniqueName < repos[j].UniqueName
-		})
-	} else {
-		sort.SliceStable(repos, func(i, j int) bool {
-			return repos[i].LastCommitEpoch > repos[j].LastCommitEpoch
-		})
+	if err := sortRepos(repos, getSortKeys(config)); err != nil {
+		logError.Fatalln(fmt.Errorf("sorting repos failed. %w", err))
 	}
+
+	/* Place worktrees under their main repos */
+
+	if config.groupWorktrees {
+		repos = groupWorktrees(repos)
+	}
+
 	if loggingLevel >= 1 {
 		logInfo.Println("repos sorted.")
 	}
@@ -136,10 +275,115 @@ func statusMain(args []string) {
 		}
 	case "m":
 		emitMarkdown(repos)
+	case "c":
+		if err := emitSeparated(repos, ','); err != nil {
+			logError.Fatalln(fmt.Errorf("emitting csv failed. %w", err))
+		}
+	case "s":
+		if err := emitSeparated(repos, '\t'); err != nil {
+			logError.Fatalln(fmt.Errorf("emitting tsv failed. %w", err))
+		}
+	case "p":
+		if err := emitTemplate(repos, thisTemplate); err != nil {
+			logError.Fatalln(fmt.Errorf("emitting template failed. %w", err))
+		}
 	}
 
 	if loggingLevel >= 1 {
 		logInfo.Println("result emitted.")
 	}
 
+	/* Summarize failures */
+
+	reportUnreadable(unreadable, config)
+
+	if failedCount > 0 {
+		logWarning.Printf("retrieving status failed for %d of %d repos.", failedCount, totalCount)
+	}
+
+	if incompleteCount > 0 || errors.Is(searchErr, context.Canceled) {
+		logWarning.Printf("interrupted, status incomplete for %d of %d repos.", incompleteCount, totalCount)
+		os.Exit(INTERRUPTED_EXIT_CODE)
+	}
+
+	if searchErr != nil {
+		os.Exit(1)
+	}
+
+	if failedCount > 0 && config.failOnError {
+		os.Exit(1)
+	}
+
+}
+
+/*
+countFailed returns the number of repos, which status could not be retrieved
+
+	'repos' slice of structures describing the repos
+*/
+func countFailed(repos []tRepo) int {
+
+	var thisCount int
+
+	for _, thisRepo := range repos {
+		if len(thisRepo.Error) > 0 {
+			thisCount++
+		}
+	}
+
+	return thisCount
+}
+
+/*
+countIncomplete returns the number of repos, which status retrieval was interrupted
+
+	'repos' slice of structures describing the repos
+*/
+func countIncomplete(repos []tRepo) int {
+
+	var thisCount int
+
+	for _, thisRepo := range repos {
+		if thisRepo.Incomplete {
+			thisCount++
+		}
+	}
+
+	return thisCount
+}
+
+/*
+groupWorktrees returns repos reordered, so linked worktrees follow their main repo
+
+	'repos' slice of structures describing the repos
+*/
+func groupWorktrees(repos []tRepo) []tRepo {
+
+	var (
+		thisResult    = make([]tRepo, 0, len(repos))
+		thisPresent   = map[string]bool{}    // Main repos within the slice
+		thisWorktrees = map[string][]tRepo{} // Worktrees by their main repo
+	)
+
+	for _, thisRepo := range repos {
+		thisPresent[thisRepo.TopLevelPath] = true
+	}
+
+	for _, thisRepo := range repos {
+		if len(thisRepo.MainRepo) > 0 && thisPresent[thisRepo.MainRepo] {
+			thisWorktrees[thisRepo.MainRepo] = append(thisWorktrees[thisRepo.MainRepo], thisRepo)
+		}
+	}
+
+	/* Worktrees without their main repo keep their place */
+
+	for _, thisRepo := range repos {
+		if len(thisRepo.MainRepo) > 0 && thisPresent[thisRepo.MainRepo] {
+			continue
+		}
+		thisResult = append(thisResult, thisRepo)
+		thisResult = append(thisResult, thisWorktrees[thisRepo.TopLevelPath]...)
+	}
+
+	return thisResult
 }
@@ -1,19 +1,25 @@
 package cmd
 
 import (
+	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/fatih/color"
 )
 
 type tColumn struct {
+	id              string         // Stable identifier, used by --columns
+	enable          func(*tConfig) // Sets config, so column's data gets retrieved, nil when always retrieved
 	isShown         func(tConfig) bool
 	title           func(tConfig) string
 	titleColor      color.Attribute
 	contentSource   func(tConfig, tRepo) string
+	contentLink     func(tRepo) string // Target of hyperlink, nil when content is not clickable
 	contentColor    func(tRepo) color.Attribute
 	contentAlignMD  int
 	contentEscapeMD bool
+	compare         func(tRepo, tRepo) int // Ascending order of values, not of their text
 }
 
 /*
@@ -23,6 +29,21 @@ func getClickable(linkText string, url string) string {
 	return "\033]8;;" + url + "\a" + linkText + "\033]8;;\a"
 }
 
+/*
+getLinked returns content made clickable, when column has a link and content is not empty
+
+	'content' text of the column, already formatted
+	'tr' repo described
+*/
+func (c tColumn) getLinked(content string, tr tRepo) string {
+
+	if c.contentLink == nil || len(content) == 0 {
+		return content
+	}
+
+	return getClickable(content, c.contentLink(tr))
+}
+
 /*
 getColumns defines look and content of table's emitted columns
 */
@@ -33,6 +54,7 @@ func getColumns() []tColumn {
 	thisColumns = append(thisColumns,
 
 		tColumn{ // Name
+			id:      "name",
 			isShown: func(_ tConfig) bool { return true }, // Always shown
 			title: func(tc tConfig) string {
 				switch tc.nameShown.Value { // Title differs by config
@@ -48,22 +70,68 @@ func getColumns() []tColumn {
 			titleColor: color.Bold,
 
 			contentSource: func(tc tConfig, tr tRepo) string {
+				var thisIndent string
+				if tc.groupWorktrees && len(tr.MainRepo) > 0 {
+					thisIndent = WORKTREE_INDENT // Worktree placed under its main repo
+				}
 				switch tc.nameShown.Value { // Content differs by config
 				case "p":
-					return getClickable(tr.TopLevelPath, "file:///"+tr.TopLevelPath)
+					return thisIndent + tr.TopLevelPath
 				case "s":
-					return getClickable(tr.ShortName, "file:///"+tr.TopLevelPath)
+					return thisIndent + tr.ShortName
 				case "u":
-					return getClickable(tr.UniqueName, "file:///"+tr.TopLevelPath)
+					return thisIndent + tr.UniqueName
 				}
 				return ""
 			},
+			contentLink:     func(tr tRepo) string { return "file:///" + tr.TopLevelPath },
 			contentColor:    func(_ tRepo) color.Attribute { return color.FgHiYellow }, // Static color
 			contentAlignMD:  ALIGN_LEFT,
 			contentEscapeMD: true,
+			compare:         func(a, b tRepo) int { return compareText(a.UniqueName, b.UniqueName) },
+		},
+
+		tColumn{ // showSubmodules, showParent
+			id:         "parent",
+			enable:     func(tc *tConfig) { tc.showParent = true },
+			isShown:    func(tc tConfig) bool { return tc.showSubmodules || tc.showParent },
+			title:      func(_ tConfig) string { return "Parent" }, // Static title
+			titleColor: color.Bold,
+
+			contentSource: func(_ tConfig, tr tRepo) string {
+				if len(tr.Parent) == 0 {
+					return ""
+				}
+				return filepath.Base(tr.Parent)
+			},
+			contentLink:     func(tr tRepo) string { return "file:///" + tr.Parent },
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgYellow }, // Static color
+			contentAlignMD:  ALIGN_LEFT,
+			contentEscapeMD: true,
+			compare:         func(a, b tRepo) int { return compareText(a.Parent, b.Parent) },
+		},
+
+		tColumn{ // showLayout
+			id:         "layout",
+			isShown:    func(tc tConfig) bool { return tc.showLayout },
+			title:      func(_ tConfig) string { return "W" }, // Static title
+			titleColor: color.Bold,
+
+			contentSource: func(_ tConfig, tr tRepo) string {
+				if tr.Bare {
+					return BARE_SYMBOL
+				}
+				return parseBool(len(tr.MainRepo) > 0, WORKTREE_SYMBOL)
+			},
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgHiMagenta }, // Static color
+			contentAlignMD:  ALIGN_CENTER,
+			contentEscapeMD: false,
+			compare:         func(a, b tRepo) int { return compareInt(getLayoutRank(a), getLayoutRank(b)) },
 		},
 
 		tColumn{ // showCommitTime
+			id:         "time",
+			enable:     func(tc *tConfig) { tc.showCommitTime = true },
 			isShown:    func(tc tConfig) bool { return tc.showCommitTime },
 			title:      func(_ tConfig) string { return "Last commit" }, // Static title
 			titleColor: color.Bold,
@@ -72,29 +140,95 @@ func getColumns() []tColumn {
 			contentColor:    func(_ tRepo) color.Attribute { return color.FgHiBlack }, // Static color
 			contentAlignMD:  ALIGN_LEFT,
 			contentEscapeMD: true,
+			compare:         func(a, b tRepo) int { return compareInt(a.LastCommitEpoch, b.LastCommitEpoch) },
+		},
+
+		tColumn{ // showCommitHash
+			id:         "hash",
+			enable:     func(tc *tConfig) { tc.showCommitHash = true },
+			isShown:    func(tc tConfig) bool { return tc.showCommitHash },
+			title:      func(_ tConfig) string { return "Hash" }, // Static title
+			titleColor: color.Bold,
+
+			contentSource:   func(_ tConfig, tr tRepo) string { return tr.LastCommitHash },
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgYellow }, // Static color
+			contentAlignMD:  ALIGN_LEFT,
+			contentEscapeMD: false,
+			compare:         func(a, b tRepo) int { return compareText(a.LastCommitHash, b.LastCommitHash) },
+		},
+
+		tColumn{ // showCommitAuthor
+			id:         "author",
+			enable:     func(tc *tConfig) { tc.showCommitAuthor = true },
+			isShown:    func(tc tConfig) bool { return tc.showCommitAuthor },
+			title:      func(_ tConfig) string { return "Author" }, // Static title
+			titleColor: color.Bold,
+
+			contentSource: func(_ tConfig, tr tRepo) string {
+				if len(tr.LastCommitAuthor) == 0 {
+					return ""
+				}
+				return tr.LastCommitAuthor + " <" + tr.LastCommitEmail + ">"
+			},
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgHiBlack }, // Static color
+			contentAlignMD:  ALIGN_LEFT,
+			contentEscapeMD: true,
+			compare:         func(a, b tRepo) int { return compareText(a.LastCommitAuthor, b.LastCommitAuthor) },
+		},
+
+		tColumn{ // showCommitSubject
+			id:         "subject",
+			enable:     func(tc *tConfig) { tc.showCommitSubject = true },
+			isShown:    func(tc tConfig) bool { return tc.showCommitSubject },
+			title:      func(_ tConfig) string { return "Subject" }, // Static title
+			titleColor: color.Bold,
+
+			contentSource:   func(_ tConfig, tr tRepo) string { return tr.LastCommitSubject },
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgWhite }, // Static color
+			contentAlignMD:  ALIGN_LEFT,
+			contentEscapeMD: true,
+			compare:         func(a, b tRepo) int { return compareText(a.LastCommitSubject, b.LastCommitSubject) },
 		},
 
 		tColumn{ // showBranchHead
+			id:         "branch",
+			enable:     func(tc *tConfig) { tc.showBranchHead = true },
 			isShown:    func(tc tConfig) bool { return tc.showBranchHead },
 			title:      func(_ tConfig) string { return "Branch head" }, // Static title
 			titleColor: color.Bold,
 
-			contentSource:   func(_ tConfig, tr tRepo) string { return tr.BranchHead },
+			contentSource:   func(_ tConfig, tr tRepo) string { return getBranchHeadText(tr) },
 			contentColor:    func(_ tRepo) color.Attribute { return color.FgHiBlue }, // Static color
 			contentAlignMD:  ALIGN_LEFT,
 			contentEscapeMD: true,
+			compare:         func(a, b tRepo) int { return compareText(getBranchHeadText(a), getBranchHeadText(b)) },
 		},
 		tColumn{ // showFetchNeeded
+			id:         "fetch",
+			enable:     func(tc *tConfig) { tc.showFetchNeeded = true },
 			isShown:    func(tc tConfig) bool { return tc.showFetchNeeded },
 			title:      func(_ tConfig) string { return "Q" }, // Static title
 			titleColor: color.Bold,
 
-			contentSource:   func(_ tConfig, tr tRepo) string { return parseBool(tr.FetchNeeded, FETCH_NEEDED_SYMBOL) },
-			contentColor:    func(_ tRepo) color.Attribute { return color.FgHiCyan }, // Static color
+			contentSource: func(_ tConfig, tr tRepo) string {
+				if tr.UpstreamGone {
+					return UPSTREAM_GONE_SYMBOL
+				}
+				return parseBool(tr.FetchNeeded, FETCH_NEEDED_SYMBOL)
+			},
+			contentColor: func(tr tRepo) color.Attribute {
+				if tr.UpstreamGone {
+					return color.FgHiRed
+				}
+				return color.FgHiCyan
+			}, // Dynamic color
 			contentAlignMD:  ALIGN_CENTER,
 			contentEscapeMD: false,
+			compare:         func(a, b tRepo) int { return compareInt(getFetchRank(a), getFetchRank(b)) },
 		},
 		tColumn{ // showBranchUpstream
+			id:         "remote",
+			enable:     func(tc *tConfig) { tc.showBranchUpstream = true },
 			isShown:    func(tc tConfig) bool { return tc.showBranchUpstream },
 			title:      func(_ tConfig) string { return "Branch remote" }, // Static title
 			titleColor: color.Bold,
@@ -103,15 +237,37 @@ func getColumns() []tColumn {
 			contentColor:    func(_ tRepo) color.Attribute { return color.FgHiBlue }, // Static color
 			contentAlignMD:  ALIGN_LEFT,
 			contentEscapeMD: true,
+			compare:         func(a, b tRepo) int { return compareText(a.BranchUpstream, b.BranchUpstream) },
+		},
+
+		tColumn{ // showDefaultAB
+			id:         "default",
+			enable:     func(tc *tConfig) { tc.showDefaultAB = true },
+			isShown:    func(tc tConfig) bool { return tc.showDefaultAB },
+			title:      func(_ tConfig) string { return "Default branch" }, // Static title
+			titleColor: color.Bold,
+
+			contentSource: func(_ tConfig, tr tRepo) string { return getDefaultABText(tr) },
+			contentColor: func(tr tRepo) color.Attribute {
+				return getThisABColor()[getStatusAB(tr.DefaultAhead, tr.DefaultBehind)]
+			}, // Dynamic color
+			contentAlignMD:  ALIGN_LEFT,
+			contentEscapeMD: true,
+			compare: func(a, b tRepo) int {
+				return compareInts([]int64{int64(a.DefaultAhead), int64(a.DefaultBehind)}, []int64{int64(b.DefaultAhead), int64(b.DefaultBehind)})
+			},
 		},
 
 		tColumn{ // showUrl
+			id:         "url",
+			enable:     func(tc *tConfig) { tc.showUrl = true },
 			isShown:    func(tc tConfig) bool { return tc.showUrl },
 			title:      func(_ tConfig) string { return "Url" }, // Static title
 			titleColor: color.Bold,
 
-			contentSource: func(_ tConfig, tr tRepo) string {
-				return getClickable(tr.OriginUrl, strings.ReplaceAll(tr.OriginUrl, "ssh://git@", "https://"))
+			contentSource: func(_ tConfig, tr tRepo) string { return tr.OriginUrl },
+			contentLink: func(tr tRepo) string {
+				return strings.ReplaceAll(tr.OriginUrl, "ssh://git@", "https://")
 				/* return strings.ReplaceAll(
 					tr.OriginUrl, "git@github.com:", "ssh@https://github.com/", // To provide clickable text in the output
 				) */
@@ -119,9 +275,24 @@ func getColumns() []tColumn {
 			contentColor:    func(_ tRepo) color.Attribute { return color.FgWhite }, // Static color
 			contentAlignMD:  ALIGN_LEFT,
 			contentEscapeMD: false,
+			compare:         func(a, b tRepo) int { return compareText(a.OriginUrl, b.OriginUrl) },
+		},
+
+		tColumn{ // showOperation
+			id:         "state",
+			isShown:    func(tc tConfig) bool { return tc.showOperation },
+			title:      func(_ tConfig) string { return "State" }, // Static title
+			titleColor: color.Bold,
+
+			contentSource:   func(_ tConfig, tr tRepo) string { return strings.ToUpper(tr.Operation) }, // Highlighted
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgHiRed },                    // Static color
+			contentAlignMD:  ALIGN_LEFT,
+			contentEscapeMD: true,
+			compare:         func(a, b tRepo) int { return compareText(a.Operation, b.Operation) },
 		},
 
 		tColumn{ // Ahead / behind
+			id:         "ab",
 			isShown:    func(tc tConfig) bool { return true },               // Always shown
 			title:      func(_ tConfig) string { return PUSH_FETCH_SYMBOL }, // Static title
 			titleColor: color.Bold,
@@ -130,9 +301,14 @@ func getColumns() []tColumn {
 			contentColor:    func(tr tRepo) color.Attribute { return getThisABColor()[tr.StatusAB] }, // Dynamic color
 			contentAlignMD:  ALIGN_CENTER,
 			contentEscapeMD: false,
+			compare: func(a, b tRepo) int {
+				return compareInts([]int64{int64(a.Ahead), int64(a.Behind)}, []int64{int64(b.Ahead), int64(b.Behind)})
+			},
 		},
 
 		tColumn{ // showDirty
+			id:         "dirty",
+			enable:     func(tc *tConfig) { tc.showDirty = true },
 			isShown:    func(tc tConfig) bool { return tc.showDirty },
 			title:      func(_ tConfig) string { return "D" }, // Static title
 			titleColor: color.Bold,
@@ -141,9 +317,77 @@ func getColumns() []tColumn {
 			contentColor:    func(_ tRepo) color.Attribute { return color.FgCyan }, // Static color
 			contentAlignMD:  ALIGN_CENTER,
 			contentEscapeMD: false,
+			compare:         func(a, b tRepo) int { return compareBool(a.Dirty, b.Dirty) },
+		},
+
+		tColumn{ // showCounts: staged
+			id:         "staged",
+			isShown:    func(tc tConfig) bool { return tc.showCounts },
+			title:      func(_ tConfig) string { return STAGED_TITLE }, // Static title
+			titleColor: color.Bold,
+
+			contentSource:   func(_ tConfig, tr tRepo) string { return parseCount(tr.Staged) },
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgGreen }, // Static color
+			contentAlignMD:  ALING_RIGHT,
+			contentEscapeMD: false,
+			compare:         func(a, b tRepo) int { return compareInt(int64(a.Staged), int64(b.Staged)) },
+		},
+
+		tColumn{ // showCounts: modified
+			id:         "modified",
+			isShown:    func(tc tConfig) bool { return tc.showCounts },
+			title:      func(_ tConfig) string { return MODIFIED_TITLE }, // Static title
+			titleColor: color.Bold,
+
+			contentSource:   func(_ tConfig, tr tRepo) string { return parseCount(tr.Modified) },
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgYellow }, // Static color
+			contentAlignMD:  ALING_RIGHT,
+			contentEscapeMD: false,
+			compare:         func(a, b tRepo) int { return compareInt(int64(a.Modified), int64(b.Modified)) },
+		},
+
+		tColumn{ // showCounts: deleted
+			id:         "deleted",
+			isShown:    func(tc tConfig) bool { return tc.showCounts },
+			title:      func(_ tConfig) string { return DELETED_TITLE }, // Static title
+			titleColor: color.Bold,
+
+			contentSource:   func(_ tConfig, tr tRepo) string { return parseCount(tr.Deleted) },
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgRed }, // Static color
+			contentAlignMD:  ALING_RIGHT,
+			contentEscapeMD: false,
+			compare:         func(a, b tRepo) int { return compareInt(int64(a.Deleted), int64(b.Deleted)) },
+		},
+
+		tColumn{ // showCounts: renamed
+			id:         "renamed",
+			isShown:    func(tc tConfig) bool { return tc.showCounts },
+			title:      func(_ tConfig) string { return RENAMED_TITLE }, // Static title
+			titleColor: color.Bold,
+
+			contentSource:   func(_ tConfig, tr tRepo) string { return parseCount(tr.Renamed) },
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgCyan }, // Static color
+			contentAlignMD:  ALING_RIGHT,
+			contentEscapeMD: false,
+			compare:         func(a, b tRepo) int { return compareInt(int64(a.Renamed), int64(b.Renamed)) },
+		},
+
+		tColumn{ // showCounts: unmerged
+			id:         "unmerged",
+			isShown:    func(tc tConfig) bool { return tc.showCounts },
+			title:      func(_ tConfig) string { return UNMERGED_TITLE }, // Static title
+			titleColor: color.Bold,
+
+			contentSource:   func(_ tConfig, tr tRepo) string { return parseCount(tr.Unmerged) },
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgHiRed }, // Static color
+			contentAlignMD:  ALING_RIGHT,
+			contentEscapeMD: false,
+			compare:         func(a, b tRepo) int { return compareInt(int64(a.Unmerged), int64(b.Unmerged)) },
 		},
 
 		tColumn{ // showUntracked
+			id:         "untracked",
+			enable:     func(tc *tConfig) { tc.showUntracked = true },
 			isShown:    func(tc tConfig) bool { return tc.showUntracked },
 			title:      func(_ tConfig) string { return "U" }, // Static title
 			titleColor: color.Bold,
@@ -152,9 +396,26 @@ func getColumns() []tColumn {
 			contentColor:    func(_ tRepo) color.Attribute { return color.FgRed }, // Static color
 			contentAlignMD:  ALIGN_CENTER,
 			contentEscapeMD: false,
+			compare:         func(a, b tRepo) int { return compareBool(a.Untracked, b.Untracked) },
+		},
+
+		tColumn{ // showCounts: untracked
+			id:         "untracked-count",
+			enable:     func(tc *tConfig) { tc.showUntracked = true },
+			isShown:    func(tc tConfig) bool { return tc.showCounts && tc.showUntracked },
+			title:      func(_ tConfig) string { return UNTRACKED_TITLE }, // Static title
+			titleColor: color.Bold,
+
+			contentSource:   func(_ tConfig, tr tRepo) string { return parseCount(tr.UntrackedCount) },
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgRed }, // Static color
+			contentAlignMD:  ALING_RIGHT,
+			contentEscapeMD: false,
+			compare:         func(a, b tRepo) int { return compareInt(int64(a.UntrackedCount), int64(b.UntrackedCount)) },
 		},
 
 		tColumn{ // showStash
+			id:      "stash",
+			enable:  func(tc *tConfig) { tc.showStash = true },
 			isShown: func(tc tConfig) bool { return tc.showStash },
 			title:   func(_ tConfig) string { return "S" }, // Static title
 
@@ -163,12 +424,193 @@ func getColumns() []tColumn {
 			contentColor:    func(_ tRepo) color.Attribute { return color.FgYellow }, // Static color
 			contentAlignMD:  ALIGN_CENTER,
 			contentEscapeMD: false,
+			compare:         func(a, b tRepo) int { return compareBool(a.Stash, b.Stash) },
+		},
+
+		tColumn{ // showCounts: stash
+			id:         "stash-count",
+			enable:     func(tc *tConfig) { tc.showStash = true },
+			isShown:    func(tc tConfig) bool { return tc.showCounts && tc.showStash },
+			title:      func(_ tConfig) string { return STASH_TITLE }, // Static title
+			titleColor: color.Bold,
+
+			contentSource:   func(_ tConfig, tr tRepo) string { return parseCount(tr.StashCount) },
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgYellow }, // Static color
+			contentAlignMD:  ALING_RIGHT,
+			contentEscapeMD: false,
+			compare:         func(a, b tRepo) int { return compareInt(int64(a.StashCount), int64(b.StashCount)) },
+		},
+
+		tColumn{ // showStashAge
+			id:         "stash-age",
+			enable:     func(tc *tConfig) { tc.showStashAge = true },
+			isShown:    func(tc tConfig) bool { return tc.showStashAge },
+			title:      func(_ tConfig) string { return "Oldest stash" }, // Static title
+			titleColor: color.Bold,
+
+			contentSource:   func(_ tConfig, tr tRepo) string { return tr.StashAge },
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgHiBlack }, // Static color
+			contentAlignMD:  ALIGN_LEFT,
+			contentEscapeMD: true,
+			compare:         func(a, b tRepo) int { return compareInt(a.StashOldestEpoch, b.StashOldestEpoch) },
+		},
+
+		tColumn{ // Group, only when listed
+			id:         "group",
+			isShown:    func(_ tConfig) bool { return false },     // Never shown by flags
+			title:      func(_ tConfig) string { return "Group" }, // Static title
+			titleColor: color.Bold,
+
+			contentSource:   func(_ tConfig, tr tRepo) string { return tr.TopLevelGroup },
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgYellow }, // Static color
+			contentAlignMD:  ALIGN_LEFT,
+			contentEscapeMD: true,
+			compare:         func(a, b tRepo) int { return compareText(a.TopLevelGroup, b.TopLevelGroup) },
+		},
+
+		tColumn{ // showError
+			id:         "error",
+			isShown:    func(tc tConfig) bool { return tc.showError },
+			title:      func(_ tConfig) string { return "E" }, // Static title
+			titleColor: color.Bold,
+
+			contentSource: func(_ tConfig, tr tRepo) string {
+				if tr.TimedOut {
+					return TIMEOUT_SYMBOL
+				}
+				if tr.Incomplete {
+					return INCOMPLETE_SYMBOL
+				}
+				return parseBool(len(tr.Error) > 0, ERROR_SYMBOL)
+			},
+			contentColor:    func(_ tRepo) color.Attribute { return color.FgHiRed }, // Static color
+			contentAlignMD:  ALIGN_CENTER,
+			contentEscapeMD: false,
+			compare: func(a, b tRepo) int {
+				return compareBool(len(a.Error) > 0 || a.Incomplete, len(b.Error) > 0 || b.Incomplete)
+			},
 		},
 	)
 
 	return thisColumns
 }
 
+/*
+getShownColumns returns columns listed in config.columns, in that order, or else columns shown by config
+
+	'thisConfig' holds the list and the flags
+*/
+func getShownColumns(thisConfig tConfig) []tColumn {
+
+	var thisResult []tColumn
+
+	thisColumns := getColumns()
+
+	if len(thisConfig.columns) == 0 {
+		for _, thisColumn := range thisColumns {
+			if thisColumn.isShown(thisConfig) {
+				thisResult = append(thisResult, thisColumn)
+			}
+		}
+		return thisResult
+	}
+
+	for _, thisId := range thisConfig.columns {
+		if thisColumn, err := getColumn(thisId); err == nil { // Validated by enableColumns
+			thisResult = append(thisResult, thisColumn)
+		}
+	}
+
+	return thisResult
+}
+
+/*
+enableColumns validates ids listed in config.columns and enables retrieval of their data
+
+	'thisConfig' (passed by reference) holds the list, to be populated with flags
+*/
+func enableColumns(thisConfig *tConfig) error {
+
+	for _, thisId := range thisConfig.columns {
+
+		thisColumn, err := getColumn(thisId)
+		if err != nil {
+			return err
+		}
+
+		if thisColumn.enable != nil {
+			thisColumn.enable(thisConfig)
+		}
+	}
+
+	return nil
+}
+
+/*
+getLayoutRank returns 2 for bare repo, 1 for linked worktree, 0 otherwise
+*/
+func getLayoutRank(tr tRepo) int64 {
+
+	switch {
+	case tr.Bare:
+		return 2
+	case len(tr.MainRepo) > 0:
+		return 1
+	}
+
+	return 0
+}
+
+/*
+getFetchRank returns 2 when upstream is gone, 1 when fetch is needed, 0 otherwise
+*/
+func getFetchRank(tr tRepo) int64 {
+
+	switch {
+	case tr.UpstreamGone:
+		return 2
+	case tr.FetchNeeded:
+		return 1
+	}
+
+	return 0
+}
+
+/*
+getBranchHeadText returns branch head, or nearest tag and short commit id when HEAD is detached, e.g. `v1.4.2~3 (a1b2c3d)`
+
+	'tr' repo to be described
+*/
+func getBranchHeadText(tr tRepo) string {
+
+	if tr.BranchHead != DETACHED_HEAD || len(tr.CommitOid) < SHORT_OID_LEN {
+		return tr.BranchHead
+	}
+
+	thisShortOid := "(" + tr.CommitOid[:SHORT_OID_LEN] + ")"
+
+	switch {
+	case len(tr.NearestTag) == 0:
+		return thisShortOid
+	case tr.TagDistance == 0:
+		return tr.NearestTag + " " + thisShortOid
+	default:
+		return fmt.Sprintf("%s~%d %s", tr.NearestTag, tr.TagDistance, thisShortOid)
+	}
+}
+
+/*
+getDefaultABText returns default branch with commits ahead / behind it, e.g. `origin/main +3 -1`
+*/
+func getDefaultABText(tr tRepo) string {
+
+	if len(tr.DefaultBranch) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s +%d -%d", tr.DefaultBranch, tr.DefaultAhead, tr.DefaultBehind)
+}
+
 /*
 Ahead / behind symbols
 
@@ -230,12 +672,32 @@ func getThisAlignChar() map[int]string {
 	}
 }
 
+/*
+Titles of file count columns
+*/
+const (
+	STAGED_TITLE    string = "+"
+	MODIFIED_TITLE  string = "~"
+	DELETED_TITLE   string = "-"
+	RENAMED_TITLE   string = "»"
+	UNMERGED_TITLE  string = "!"
+	UNTRACKED_TITLE string = "?"
+	STASH_TITLE     string = "≡"
+)
+
 /*
 Other symbols
 */
 const (
-	DIRTY_SYMBOL        string = "⊛"
-	UNTRACKED_SYMBOL    string = "⊗"
-	STASH_SYMBOL        string = "⊜"
-	FETCH_NEEDED_SYMBOL string = "↯" // Ready for fetch
+	DIRTY_SYMBOL         string = "⊛"
+	UNTRACKED_SYMBOL     string = "⊗"
+	STASH_SYMBOL         string = "⊜"
+	FETCH_NEEDED_SYMBOL  string = "↯" // Ready for fetch
+	UPSTREAM_GONE_SYMBOL string = "⊝" // Upstream deleted on remote
+	ERROR_SYMBOL         string = "⊠" // Retrieving status failed
+	TIMEOUT_SYMBOL       string = "⧖" // Retrieving status timed out
+	INCOMPLETE_SYMBOL    string = "…" // Retrieving status interrupted
+	BARE_SYMBOL          string = "⊡" // Bare repo
+	WORKTREE_SYMBOL      string = "⎗" // Linked worktree
+	WORKTREE_INDENT      string = "└ "
 )
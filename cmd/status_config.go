@@ -1,5 +1,7 @@
 package cmd
 
+import "time"
+
 /*
 Status' configuration
 */
@@ -7,16 +9,52 @@ type tConfig struct {
 	sortOrder          *tChoice
 	nameShown          *tChoice
 	timeFormat         *tChoice
+	dateKind           *tChoice // Committer or author date of last commit
 	showUrl            bool
 	showCommitTime     bool
+	showCommitHash     bool
+	showCommitAuthor   bool
+	showCommitSubject  bool
 	showBranchHead     bool
 	showFetchNeeded    bool
 	showBranchUpstream bool
+	showDefaultAB      bool // Ahead / behind default branch shown
 	showDirty          bool
 	showUntracked      bool
+	showCounts         bool // File counts shown by kind of change
 	showStash          bool
-	lookForSubGits     bool // Not implemented
+	showStashAge       bool          // Time of the oldest stash shown
+	lookForSubGits     bool          // Walk below found repos
+	showSubmodules     bool          // Report submodules, implies lookForSubGits
+	showParent         bool          // Superproject queried, leaving the search as is
+	excludes           []string      // Globs of dirs not to be searched
+	maxDepth           int           // Depth of search, negative for unlimited
+	followSymlinks     bool          // Walk into symlinked dirs
+	listUnreadable     bool          // List paths skipped, as could not be read
+	fromFile           string        // File listing repos, `-` for standard input
+	cacheTTL           time.Duration // Age of search results still reused, none when not positive
+	rescan             bool          // Search again, refreshing the cache
+	jobs               int           // Number of repos queried concurrently
+	gitTimeout         time.Duration // Time allowed for a single git command, none when not positive
+	deadline           time.Duration // Time allowed for the whole run, none when not positive
+	showError          bool          // Set when any repo failed
+	failOnError        bool          // Exit with non-zero code when any repo failed
+	showLayout         bool          // Set when any repo is bare or linked worktree
+	showOperation      bool          // Set when any repo has operation in progress
+	groupWorktrees     bool          // Place linked worktrees under their main repos
 	emitFormat         *tChoice
+	columns            []string // Ids of columns shown, in order, overriding the flags
+	sortKeys           []string // Column ids, `-` prefixed when descending, overriding sortOrder
+	dirtyOnly          bool     // Only repos with changes to tracked files
+	aheadOnly          bool     // Only repos with commits to push
+	behindOnly         bool     // Only repos with commits to merge
+	divergedOnly       bool     // Only repos both ahead and behind
+	noUpstreamOnly     bool     // Only repos without upstream
+	onBranch           string   // Only repos with the branch checked out
+	where              []string // Only repos matching expressions on json fields
+	template           string   // Go text/template rendering repos
+	templateFile       string   // File holding the template
+	templateScope      *tChoice // Template rendering each repo, or all of them
 }
 
 /*
@@ -26,5 +64,7 @@ func initChoices() {
 	config.nameShown = newChoice([]string{"u", "p", "s"}, "u")
 	config.sortOrder = newChoice([]string{"t", "n"}, "t")
 	config.timeFormat = newChoice([]string{"r", "i"}, "r")
-	config.emitFormat = newChoice([]string{"t", "j", "m"}, "t")
+	config.dateKind = newChoice([]string{"c", "a"}, "c")
+	config.emitFormat = newChoice([]string{"t", "j", "m", "c", "s", "p"}, "t")
+	config.templateScope = newChoice([]string{"r", "a"}, "r")
 }
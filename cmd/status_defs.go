@@ -1,21 +1,68 @@
 package cmd
 
+import "time"
+
+const DETACHED_HEAD string = "(detached)" // Branch head, when HEAD is detached
+const INITIAL_OID string = "(initial)"    // Commit id, when no commits yet
+
+/*
+Operations left in progress
+*/
+const (
+	MERGE_OPERATION       string = "merge"
+	REBASE_OPERATION      string = "rebase"
+	AM_OPERATION          string = "am"
+	CHERRY_PICK_OPERATION string = "cherry-pick"
+	REVERT_OPERATION      string = "revert"
+	BISECT_OPERATION      string = "bisect"
+)
+
 // https://mattkrol.me/2020/display-git-status-information-in-your-shell-prompt.html
 type tRepo struct {
-	TopLevelPath    string `json:"topLevelPath"`    // Full path
-	UniqueName      string `json:"uniqueName"`      // Shortest unique path
-	TopLevelGroup   string `json:"topLevelGroup"`   // Most significant segment
-	ShortName       string `json:"shortName"`       // Least significant segment
-	OriginUrl       string `json:"originUrl"`       // github url
-	LastCommitTime  string `json:"lastCommitTime"`  // Human-readable
-	LastCommitEpoch string `json:"lastCommitEpoch"` // For sorting purposes
-	BranchHead      string `json:"branchHead"`
-	FetchNeeded     bool   `json:"fetchNeeded"`
-	BranchUpstream  string `json:"branchUpstream"`
-	Ahead           int    `json:"ahead"`
-	Behind          int    `json:"behind"`
-	StatusAB        string `json:"statusAB"` // Ahead - behind
-	Dirty           bool   `json:"dirty"`
-	Untracked       bool   `json:"untracked"`
-	Stash           bool   `json:"stash"`
+	TopLevelPath      string     `json:"topLevelPath"`    // Full path
+	UniqueName        string     `json:"uniqueName"`      // Shortest unique path
+	TopLevelGroup     string     `json:"topLevelGroup"`   // Most significant segment
+	ShortName         string     `json:"shortName"`       // Least significant segment
+	OriginUrl         string     `json:"originUrl"`       // github url
+	LastCommitTime    string     `json:"lastCommitTime"`  // Human-readable
+	LastCommitEpoch   int64      `json:"lastCommitEpoch"` // For sorting purposes
+	LastCommitDate    *time.Time `json:"lastCommitDate"`  // RFC3339, null when no commits
+	LastCommitHash    string     `json:"lastCommitHash"`  // Abbreviated
+	LastCommitAuthor  string     `json:"lastCommitAuthor"`
+	LastCommitEmail   string     `json:"lastCommitEmail"` // Author's
+	LastCommitSubject string     `json:"lastCommitSubject"`
+	BranchHead        string     `json:"branchHead"`
+	CommitOid         string     `json:"commitOid"`   // HEAD's commit id
+	NearestTag        string     `json:"nearestTag"`  // Tag reachable from detached HEAD
+	TagDistance       int        `json:"tagDistance"` // Commits on top of NearestTag
+	FetchNeeded       bool       `json:"fetchNeeded"`
+	UpstreamGone      bool       `json:"upstreamGone"` // Upstream branch deleted on remote
+	BranchUpstream    string     `json:"branchUpstream"`
+	Ahead             int        `json:"ahead"`
+	Behind            int        `json:"behind"`
+	StatusAB          string     `json:"statusAB"`      // Ahead - behind
+	DefaultBranch     string     `json:"defaultBranch"` // Remote's default branch, e.g. origin/main
+	DefaultAhead      int        `json:"defaultAhead"`  // Commits on HEAD only
+	DefaultBehind     int        `json:"defaultBehind"` // Commits on DefaultBranch only
+	Dirty             bool       `json:"dirty"`
+	Staged            int        `json:"staged"`
+	Modified          int        `json:"modified"`
+	Deleted           int        `json:"deleted"`
+	Renamed           int        `json:"renamed"`
+	Unmerged          int        `json:"unmerged"`
+	Untracked         bool       `json:"untracked"`
+	UntrackedCount    int        `json:"untrackedCount"`
+	Stash             bool       `json:"stash"`
+	StashCount        int        `json:"stashCount"`
+	StashAge          string     `json:"stashAge"` // Human-readable time of the oldest stash
+	StashOldestEpoch  int64      `json:"stashOldestEpoch"`
+	StashOldestDate   *time.Time `json:"stashOldestDate"` // RFC3339, null when no stash
+	Error             string     `json:"error"`           // Why retrieving repo's status failed
+	TimedOut          bool       `json:"timedOut"`        // Retrieving repo's status took too long
+	Incomplete        bool       `json:"incomplete"`      // Retrieving repo's status was interrupted
+	Parent            string     `json:"parent"`          // Superproject's full path, when submodule
+	GitDir            string     `json:"gitDir"`          // Full path of git's database
+	Bare              bool       `json:"bare"`            // Has no work tree
+	MainRepo          string     `json:"mainRepo"`        // Main repo's full path, when linked worktree
+	Operation         string     `json:"operation"`       // Merge, rebase etc. left in progress
 }
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/color"
@@ -24,6 +27,20 @@ func parseBool(thisBool bool, thisString string) string {
 	return ""
 }
 
+/*
+parseCount returns thisCount as text, empty if zero
+
+	'thisCount' value to be converted
+*/
+func parseCount(thisCount int) string {
+
+	if thisCount != 0 {
+		return strconv.Itoa(thisCount)
+	}
+
+	return ""
+}
+
 /*
 escapeMarkdown returns same string but safeguarderd against markdown interpretation
 
@@ -39,6 +56,7 @@ func escapeMarkdown(text string) string {
 		"(", ")",
 		"#", ".", "!",
 		"+", "-",
+		"|", "<", ">", // Commit subjects and author emails may hold these
 	}
 
 	for _, thisNeed := range needEscape {
@@ -77,22 +95,18 @@ func emitTable(repos []tRepo) error {
 
 	table := new(tabby.Table)
 
-	thisColumns := getColumns()
+	thisColumns := getShownColumns(config)
 
 	var thisHeader []string
 
 	/* Building slice of titles */
 
 	for _, thisColumn := range thisColumns {
-		if thisColumn.isShown(config) {
-
-			thisHeader = append(thisHeader,
-				color.New(thisColumn.titleColor).SprintFunc()(
-					thisColumn.title(config),
-				),
-			)
-
-		}
+		thisHeader = append(thisHeader,
+			color.New(thisColumn.titleColor).SprintFunc()(
+				thisColumn.title(config),
+			),
+		)
 	}
 
 	/* Set the header */
@@ -114,14 +128,11 @@ func emitTable(repos []tRepo) error {
 		/* Building slice of columns within a single row*/
 
 		for _, thisColumn := range thisColumns {
-
-			if thisColumn.isShown(config) {
-				thisRow = append(thisRow,
-					color.New(thisColumn.contentColor(thisRepo)).SprintFunc()(
-						thisColumn.contentSource(config, thisRepo),
-					),
-				)
-			}
+			thisRow = append(thisRow,
+				color.New(thisColumn.contentColor(thisRepo)).SprintFunc()(
+					thisColumn.getLinked(thisColumn.contentSource(config, thisRepo), thisRepo),
+				),
+			)
 		}
 
 		if err := table.AppendRow(thisRow); err != nil {
@@ -153,16 +164,14 @@ emitMarkdown prints result in the form of markdown table
 	'repos' slice of structures describing the repos
 */
 func emitMarkdown(repos []tRepo) {
-	thisColumns := getColumns()
+	thisColumns := getShownColumns(config)
 
 	var thisHeader []string
 
 	/* Building slice of titles */
 
 	for _, thisColumn := range thisColumns {
-		if thisColumn.isShown(config) {
-			thisHeader = append(thisHeader, thisColumn.title(config))
-		}
+		thisHeader = append(thisHeader, thisColumn.title(config))
 	}
 
 	/* Emitting titles */
@@ -178,9 +187,7 @@ func emitMarkdown(repos []tRepo) {
 	var thisSeparator []string
 
 	for _, thisColumn := range thisColumns {
-		if thisColumn.isShown(config) {
-			thisSeparator = append(thisSeparator, getThisAlignChar()[thisColumn.contentAlignMD])
-		}
+		thisSeparator = append(thisSeparator, getThisAlignChar()[thisColumn.contentAlignMD])
 	}
 	fmt.Println("| " + strings.Join(thisSeparator, " | ") + " |")
 
@@ -197,12 +204,10 @@ func emitMarkdown(repos []tRepo) {
 		/* Building slice of columns within a single row*/
 
 		for _, thisColumn := range thisColumns {
-			if thisColumn.isShown(config) {
-				if thisColumn.contentEscapeMD {
-					thisRow = append(thisRow, escapeMarkdown(thisColumn.contentSource(config, thisRepo)))
-				} else {
-					thisRow = append(thisRow, thisColumn.contentSource(config, thisRepo))
-				}
+			if thisColumn.contentEscapeMD {
+				thisRow = append(thisRow, thisColumn.getLinked(escapeMarkdown(thisColumn.contentSource(config, thisRepo)), thisRepo))
+			} else {
+				thisRow = append(thisRow, thisColumn.getLinked(thisColumn.contentSource(config, thisRepo), thisRepo))
 			}
 		}
 
@@ -215,3 +220,60 @@ func emitMarkdown(repos []tRepo) {
 		logInfo.Printf("%d rows printed.\n", len(repos))
 	}
 }
+
+/*
+emitSeparated prints result as delimiter separated values, with raw content: no colors, no hyperlinks
+
+	'repos' slice of structures describing the repos
+	'delimiter' comma for CSV, tab for TSV
+*/
+func emitSeparated(repos []tRepo, delimiter rune) error {
+
+	/* Raw content, worktrees are not indented */
+
+	thisConfig := config
+	thisConfig.groupWorktrees = false
+
+	thisColumns := getShownColumns(thisConfig)
+
+	thisWriter := csv.NewWriter(os.Stdout)
+	thisWriter.Comma = delimiter
+
+	/* Emitting titles */
+
+	var thisHeader []string
+
+	for _, thisColumn := range thisColumns {
+		thisHeader = append(thisHeader, thisColumn.title(thisConfig))
+	}
+
+	if err := thisWriter.Write(thisHeader); err != nil {
+		return fmt.Errorf("emitSeparated: writing header failed. %w", err)
+	}
+
+	/* Iterating through repos */
+
+	for _, thisRepo := range repos {
+
+		var thisRow []string
+
+		for _, thisColumn := range thisColumns {
+			thisRow = append(thisRow, thisColumn.contentSource(thisConfig, thisRepo))
+		}
+
+		if err := thisWriter.Write(thisRow); err != nil {
+			return fmt.Errorf("emitSeparated: writing row failed. %w", err)
+		}
+	}
+
+	thisWriter.Flush()
+	if err := thisWriter.Error(); err != nil {
+		return fmt.Errorf("emitSeparated: flushing failed. %w", err)
+	}
+
+	if loggingLevel >= 2 {
+		logInfo.Printf("%d rows printed.\n", len(repos))
+	}
+
+	return nil
+}
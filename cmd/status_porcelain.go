@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+Content of `git status --branch --porcelain=2` output
+
+https://git-scm.com/docs/git-status#_porcelain_format_version_2
+*/
+type tPorcelain struct {
+	branchOid      string // Commit id, `(initial)` when no commits yet
+	branchHead     string // Branch name, `(detached)` when detached
+	branchUpstream string
+	hasAB          bool // Set when upstream exists
+	ahead          int
+	behind         int
+	stash          int
+	staged         int // Changes in index
+	modified       int // Changes in work tree, intent-to-add included
+	deleted        int // Deleted in index or in work tree
+	renamed        int // Renamed or copied
+	unmerged       int // Conflicts
+	untracked      int
+}
+
+/*
+isDirty returns if there are any changes to tracked files
+*/
+func (p tPorcelain) isDirty() bool {
+	return p.staged+p.modified+p.deleted+p.renamed+p.unmerged > 0
+}
+
+/*
+parsePorcelainV2 returns parsed output of `git status --branch --porcelain=2`
+
+	'output' text to be parsed
+*/
+func parsePorcelainV2(output string) (tPorcelain, error) {
+
+	var thisResult tPorcelain
+
+	for _, thisLine := range strings.Split(output, "\n") {
+
+		if len(thisLine) == 0 {
+			continue
+		}
+
+		switch thisLine[0] {
+
+		case '#': // Headers
+			if err := parsePorcelainHeader(&thisResult, thisLine); err != nil {
+				return thisResult, err
+			}
+
+		case '1', '2': // Ordinary, renamed or copied entries
+			thisFields := strings.SplitN(thisLine, " ", 3)
+			if len(thisFields) < 3 || len(thisFields[1]) != 2 {
+				return thisResult, fmt.Errorf("malformed entry: %s", thisLine)
+			}
+
+			thisIndex, thisWorkTree := thisFields[1][0], thisFields[1][1]
+
+			if thisIndex != '.' {
+				thisResult.staged++
+			}
+			if thisWorkTree == 'M' || thisWorkTree == 'T' || thisWorkTree == 'A' { // Added with intent to add
+				thisResult.modified++
+			}
+			if thisIndex == 'D' || thisWorkTree == 'D' {
+				thisResult.deleted++
+			}
+			if thisLine[0] == '2' {
+				thisResult.renamed++
+			}
+
+		case 'u': // Unmerged entries
+			thisResult.unmerged++
+
+		case '?': // Untracked entries
+			thisResult.untracked++
+		}
+	}
+
+	return thisResult, nil
+}
+
+/*
+parsePorcelainHeader populates header information
+
+	'thisResult' structure (passed by refereferce) to be populated
+	'line' header line, starting with `# `
+*/
+func parsePorcelainHeader(thisResult *tPorcelain, line string) error {
+
+	thisFields := strings.Fields(line)
+	if len(thisFields) < 3 {
+		return nil // Unknown headers are to be ignored
+	}
+
+	var err error
+
+	switch thisFields[1] {
+	case "branch.oid":
+		thisResult.branchOid = thisFields[2]
+	case "branch.head":
+		thisResult.branchHead = strings.TrimPrefix(line, "# branch.head ")
+	case "branch.upstream":
+		thisResult.branchUpstream = strings.TrimPrefix(line, "# branch.upstream ")
+	case "branch.ab":
+		if len(thisFields) != 4 {
+			return fmt.Errorf("malformed header: %s", line)
+		}
+		if thisResult.ahead, err = strconv.Atoi(strings.TrimPrefix(thisFields[2], "+")); err != nil {
+			return fmt.Errorf("converting ahead to int failed. %w", err)
+		}
+		if thisResult.behind, err = strconv.Atoi(strings.TrimPrefix(thisFields[3], "-")); err != nil {
+			return fmt.Errorf("converting behind to int failed. %w", err)
+		}
+		thisResult.hasAB = true
+	case "stash":
+		if thisResult.stash, err = strconv.Atoi(thisFields[2]); err != nil {
+			return fmt.Errorf("converting stash to int failed. %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/*
+getSortKeys returns keys given with --sort, or else the ones --order stands for
+
+	'thisConfig' holds the keys and the order
+*/
+func getSortKeys(thisConfig tConfig) []string {
+
+	if len(thisConfig.sortKeys) > 0 {
+		return thisConfig.sortKeys
+	}
+
+	if thisConfig.sortOrder.Value == "n" {
+		return []string{"name"}
+	}
+
+	return []string{"-time"} // Most recent first
+}
+
+/*
+isSortedBy returns if column is one of the sort keys
+
+	'thisConfig' holds the keys
+	'id' column's identifier
+*/
+func isSortedBy(thisConfig tConfig, id string) bool {
+
+	for _, thisKey := range getSortKeys(thisConfig) {
+		if strings.TrimPrefix(thisKey, "-") == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+enableSortKeys validates ids given with --sort and enables retrieval of their data
+
+	'thisConfig' (passed by reference) holds the keys, to be populated with flags
+*/
+func enableSortKeys(thisConfig *tConfig) error {
+
+	for _, thisKey := range thisConfig.sortKeys {
+
+		thisColumn, err := getColumn(strings.TrimPrefix(thisKey, "-"))
+		if err != nil {
+			return err
+		}
+
+		if thisColumn.enable != nil {
+			thisColumn.enable(thisConfig)
+		}
+	}
+
+	return nil
+}
+
+/*
+sortRepos sorts repos in place, by each key in turn, descending when key is prefixed with `-`
+
+	'repos' slice of structures describing the repos
+	'keys' column ids, e.g. `-dirty,group,name`
+*/
+func sortRepos(repos []tRepo, keys []string) error {
+
+	var (
+		thisCompares []func(tRepo, tRepo) int
+		thisSigns    []int
+	)
+
+	for _, thisKey := range keys {
+
+		thisColumn, err := getColumn(strings.TrimPrefix(thisKey, "-"))
+		if err != nil {
+			return err
+		}
+
+		thisSign := 1
+		if strings.HasPrefix(thisKey, "-") {
+			thisSign = -1
+		}
+
+		thisCompares = append(thisCompares, thisColumn.compare)
+		thisSigns = append(thisSigns, thisSign)
+	}
+
+	sort.SliceStable(repos, func(i, j int) bool {
+		for k, thisCompare := range thisCompares {
+			if thisResult := thisCompare(repos[i], repos[j]) * thisSigns[k]; thisResult != 0 {
+				return thisResult < 0
+			}
+		}
+		return false
+	})
+
+	return nil
+}
+
+/*
+getColumn returns column of given id
+
+	'id' column's identifier
+*/
+func getColumn(id string) (tColumn, error) {
+
+	var thisIds []string
+
+	for _, thisColumn := range getColumns() {
+		if thisColumn.id == id {
+			return thisColumn, nil
+		}
+		thisIds = append(thisIds, thisColumn.id)
+	}
+
+	return tColumn{}, fmt.Errorf("unknown column %q, expected one of: %s", id, strings.Join(thisIds, ","))
+}
+
+/*
+compareInt returns -1, 0 or 1, as a is less, equal or greater than b
+*/
+func compareInt(a int64, b int64) int {
+
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+/*
+compareInts compares slices of equal length element by element
+*/
+func compareInts(a []int64, b []int64) int {
+
+	for i := range a {
+		if thisResult := compareInt(a[i], b[i]); thisResult != 0 {
+			return thisResult
+		}
+	}
+
+	return 0
+}
+
+/*
+compareBool compares bools, false being less than true
+*/
+func compareBool(a bool, b bool) int {
+
+	switch {
+	case a == b:
+		return 0
+	case b:
+		return -1
+	}
+
+	return 1
+}
+
+/*
+compareText compares strings byte-wise
+*/
+func compareText(a string, b string) int {
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/fatih/color"
+)
+
+/*
+Symbols available to templates, by name
+*/
+var templateSymbols = map[string]string{
+	"dirty":      DIRTY_SYMBOL,
+	"untracked":  UNTRACKED_SYMBOL,
+	"stash":      STASH_SYMBOL,
+	"fetch":      FETCH_NEEDED_SYMBOL,
+	"gone":       UPSTREAM_GONE_SYMBOL,
+	"error":      ERROR_SYMBOL,
+	"timeout":    TIMEOUT_SYMBOL,
+	"incomplete": INCOMPLETE_SYMBOL,
+	"bare":       BARE_SYMBOL,
+	"worktree":   WORKTREE_SYMBOL,
+	"synced":     SYNCED_SYMBOL,
+	"behind":     REMOTE_AHEAD_SYMBOL,
+	"ahead":      LOCAL_AHEAD_SYMBOL,
+	"diverged":   DIVERGED_SYMBOL,
+}
+
+/*
+Colors available to templates, by name
+*/
+var templateColors = map[string]color.Attribute{
+	"bold":      color.Bold,
+	"black":     color.FgBlack,
+	"red":       color.FgRed,
+	"green":     color.FgGreen,
+	"yellow":    color.FgYellow,
+	"blue":      color.FgBlue,
+	"magenta":   color.FgMagenta,
+	"cyan":      color.FgCyan,
+	"white":     color.FgWhite,
+	"hiblack":   color.FgHiBlack,
+	"hired":     color.FgHiRed,
+	"higreen":   color.FgHiGreen,
+	"hiyellow":  color.FgHiYellow,
+	"hiblue":    color.FgHiBlue,
+	"himagenta": color.FgHiMagenta,
+	"hicyan":    color.FgHiCyan,
+	"hiwhite":   color.FgHiWhite,
+}
+
+/*
+getTemplateFuncs returns helper functions available to templates
+*/
+func getTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+
+		/* `symbol "dirty"` */
+
+		"symbol": func(name string) (string, error) {
+			if thisSymbol, ok := templateSymbols[name]; ok {
+				return thisSymbol, nil
+			}
+			return "", fmt.Errorf("unknown symbol %q", name)
+		},
+
+		/* `color "hired" .BranchHead` */
+
+		"color": func(name string, text string) (string, error) {
+			if thisColor, ok := templateColors[name]; ok {
+				return color.New(thisColor).Sprint(text), nil
+			}
+			return "", fmt.Errorf("unknown color %q", name)
+		},
+
+		/* `abSymbol .StatusAB`, `abColor .StatusAB "text"` */
+
+		"abSymbol": func(statusAB string) string { return getThisABSymbol()[statusAB] },
+		"abColor": func(statusAB string, text string) string {
+			if thisColor, ok := getThisABColor()[statusAB]; ok {
+				return color.New(thisColor).Sprint(text)
+			}
+			return text
+		},
+
+		/* `column "branch" .`, content of the column as emitted by table, uncolored */
+
+		"column": func(id string, tr tRepo) (string, error) {
+			thisColumn, err := getColumn(id)
+			if err != nil {
+				return "", err
+			}
+			return thisColumn.contentSource(config, tr), nil
+		},
+
+		/* `link .TopLevelPath "text"` */
+
+		"link": func(url string, text string) string { return getClickable(text, url) },
+	}
+}
+
+/*
+getTemplate returns template given with --template, or read of --template-file
+
+	'thisConfig' holds the template or its file name
+*/
+func getTemplate(thisConfig tConfig) (*template.Template, error) {
+
+	thisText := thisConfig.template
+
+	if len(thisConfig.templateFile) > 0 {
+		thisBytes, err := os.ReadFile(thisConfig.templateFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s failed. %w", thisConfig.templateFile, err)
+		}
+		thisText = string(thisBytes)
+	}
+
+	if len(thisText) == 0 {
+		return nil, fmt.Errorf("no template given, use --template or --template-file")
+	}
+
+	thisTemplate, err := template.New("gitas").Option("missingkey=error").Funcs(getTemplateFuncs()).Parse(thisText)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template failed. %w", err)
+	}
+
+	return thisTemplate, nil
+}
+
+/*
+emitTemplate prints result rendered by the template, once for each repo or once for all of them
+
+	'repos' slice of structures describing the repos
+	'thisTemplate' parsed template
+*/
+func emitTemplate(repos []tRepo, thisTemplate *template.Template) error {
+
+	/* Whole slice rendered at once */
+
+	if config.templateScope.Value == "a" {
+		if err := thisTemplate.Execute(os.Stdout, repos); err != nil {
+			return fmt.Errorf("executing template failed. %w", err)
+		}
+		return nil
+	}
+
+	/* Each repo on its own line */
+
+	for _, thisRepo := range repos {
+
+		var thisBuffer bytes.Buffer
+
+		if err := thisTemplate.Execute(&thisBuffer, thisRepo); err != nil {
+			return fmt.Errorf("executing template for %s failed. %w", thisRepo.TopLevelPath, err)
+		}
+
+		fmt.Println(strings.TrimSuffix(thisBuffer.String(), "\n"))
+	}
+
+	if loggingLevel >= 2 {
+		logInfo.Printf("%d repos rendered.\n", len(repos))
+	}
+
+	return nil
+}
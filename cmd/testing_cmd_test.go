@@ -1,8 +1,18 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func Test_statusMain(t *testing.T) {
@@ -19,6 +29,7 @@ func Test_statusMain(t *testing.T) {
 			sortOrder:          &tChoice{Value: "t"},
 			nameShown:          &tChoice{Value: "u"},
 			timeFormat:         &tChoice{Value: "i"},
+			dateKind:           &tChoice{Value: "c"},
 			showUrl:            true,
 			showCommitTime:     true,
 			showBranchHead:     true,
@@ -32,6 +43,7 @@ func Test_statusMain(t *testing.T) {
 			sortOrder:          &tChoice{Value: "n"},
 			nameShown:          &tChoice{Value: "p"},
 			timeFormat:         &tChoice{Value: "r"},
+			dateKind:           &tChoice{Value: "a"},
 			showUrl:            true,
 			showCommitTime:     true,
 			showBranchHead:     true,
@@ -45,6 +57,7 @@ func Test_statusMain(t *testing.T) {
 			sortOrder:          &tChoice{Value: "t"},
 			nameShown:          &tChoice{Value: "s"},
 			timeFormat:         &tChoice{Value: "i"},
+			dateKind:           &tChoice{Value: "c"},
 			showUrl:            true,
 			showCommitTime:     true,
 			showBranchHead:     true,
@@ -119,6 +132,147 @@ func Test_emitJson(t *testing.T) {
 	}
 }
 
+func Test_emitSeparated(t *testing.T) {
+	thisSaved := config
+	defer func() { config = thisSaved }()
+
+	config = tConfig{
+		nameShown:      &tChoice{Value: "u"},
+		timeFormat:     &tChoice{Value: "r"},
+		columns:        []string{"name", "time", "dirty"},
+		groupWorktrees: true,
+	}
+
+	thisRepos := []tRepo{
+		{UniqueName: "a,b", TopLevelPath: "/a,b", LastCommitTime: "2 days ago"},
+		{UniqueName: "wt", TopLevelPath: "/wt", MainRepo: "/a,b", Dirty: true}}
+
+	type args struct {
+		repos     []tRepo
+		delimiter rune
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{"nil", args{[]tRepo{}, ','}, "Unique name,Last commit,D\n", false},
+		{"csv", args{thisRepos, ','}, "Unique name,Last commit,D\n\"a,b\",2 days ago,\nwt,," + DIRTY_SYMBOL + "\n", false},
+		{"tsv", args{thisRepos, '\t'}, "Unique name\tLast commit\tD\na,b\t2 days ago\t\nwt\t\t" + DIRTY_SYMBOL + "\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			got := captureStdout(t, func() { err = emitSeparated(tt.args.repos, tt.args.delimiter) })
+			if (err != nil) != tt.wantErr {
+				t.Errorf("emitSeparated() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("emitSeparated() = %q, want %q", got, tt.want)
+			}
+			if strings.Contains(got, "\033") {
+				t.Errorf("emitSeparated() = %q, want no escape sequences", got)
+			}
+		})
+	}
+}
+
+/*
+captureStdout returns what the function printed to standard output
+*/
+func captureStdout(tb testing.TB, f func()) string {
+	tb.Helper()
+
+	thisReader, thisWriter, err := os.Pipe()
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	thisSaved := os.Stdout
+	os.Stdout = thisWriter
+	f()
+	os.Stdout = thisSaved
+	thisWriter.Close()
+
+	thisBytes, err := io.ReadAll(thisReader)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return string(thisBytes)
+}
+
+func Test_getLinked(t *testing.T) {
+	thisLinked := tColumn{contentLink: func(tr tRepo) string { return "file:///" + tr.TopLevelPath }}
+
+	type args struct {
+		thisColumn tColumn
+		content    string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"no link", args{tColumn{}, "a"}, "a"},
+		{"empty", args{thisLinked, ""}, ""},
+		{"linked", args{thisLinked, "a"}, getClickable("a", "file:////x/a")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.args.thisColumn.getLinked(tt.args.content, tRepo{TopLevelPath: "/x/a"}); got != tt.want {
+				t.Errorf("getLinked() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getTemplate(t *testing.T) {
+	thisRepo := tRepo{UniqueName: "gitas", BranchHead: "main", Dirty: true, StatusAB: DIVERGED_CHAR}
+
+	type args struct {
+		thisConfig tConfig
+	}
+	tests := []struct {
+		name     string
+		args     args
+		want     string
+		wantErr  bool
+		wantExec bool // Error expected when executed
+	}{
+		{"nil", args{tConfig{}}, "", true, false},
+		{"broken", args{tConfig{template: "{{.UniqueName"}}, "", true, false},
+		{"missing file", args{tConfig{templateFile: "/nonexistent/gitas.tmpl"}}, "", true, false},
+		{"fields", args{tConfig{template: "{{.UniqueName}} {{.BranchHead}}{{if .Dirty}}*{{end}}"}}, "gitas main*", false, false},
+		{"symbols", args{tConfig{template: `{{symbol "dirty"}}{{abSymbol .StatusAB}}`}}, DIRTY_SYMBOL + DIVERGED_SYMBOL, false, false},
+		{"unknown symbol", args{tConfig{template: `{{symbol "x"}}`}}, "", false, true},
+		{"unknown color", args{tConfig{template: `{{color "x" .UniqueName}}`}}, "", false, true},
+		{"unknown field", args{tConfig{template: "{{.Bogus}}"}}, "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thisTemplate, err := getTemplate(tt.args.thisConfig)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getTemplate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			var got strings.Builder
+			err = thisTemplate.Execute(&got, thisRepo)
+			if (err != nil) != tt.wantExec {
+				t.Errorf("Execute() error = %v, wantExec %v", err, tt.wantExec)
+				return
+			}
+			if !tt.wantExec && got.String() != tt.want {
+				t.Errorf("Execute() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
 func Test_shellMain(t *testing.T) {
 	type args struct {
 		args []string
@@ -178,6 +332,7 @@ func Test_escapeMarkdown(t *testing.T) {
 		{"utf-8", args{"Łukasz"}, "Łukasz"},
 		{"escaping", args{"Łuk_asz"}, `Łuk\_asz`},
 		{"moreescaping", args{"Łuk-_.asz"}, `Łuk\-\_\.asz`},
+		{"author", args{"Ł <l|l>"}, `Ł \<l\|l\>`},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -253,26 +408,1185 @@ func Test_checkLogginglevel(t *testing.T) {
 	}
 }
 
-func Test_getStringRegex(t *testing.T) {
+func Test_getNamedRepos(t *testing.T) {
 	type args struct {
-		expression string
-		input      string
+		gitsSlice []string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantUnique []string
+		wantGroup  []string
+	}{
+		{"nil", args{[]string{}}, nil, nil},
+		{"single", args{[]string{"/home/x/src/a"}}, []string{"a"}, []string{"a"}},
+		{"shared", args{[]string{"/home/x/src/a", "/home/x/src/b/c"}}, []string{"a", "b/c"}, []string{"a", "b"}},
+		{"root only", args{[]string{"/home/x/src/a", "/opt/projects/b"}},
+			[]string{"home/x/src/a", "opt/projects/b"}, []string{"home", "opt"}},
+		{"prefix is repo", args{[]string{"/src/a", "/src/a/b"}}, []string{"a", "b"}, []string{"a", "b"}},
+		{"prefix repeated", args{[]string{"/src/x/src/a", "/src/y"}}, []string{"x/src/a", "y"}, []string{"x", "y"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUnique, gotGroup []string
+			for _, thisRepo := range getNamedRepos(tt.args.gitsSlice) {
+				gotUnique = append(gotUnique, thisRepo.UniqueName)
+				gotGroup = append(gotGroup, thisRepo.TopLevelGroup)
+			}
+			if !reflect.DeepEqual(gotUnique, tt.wantUnique) {
+				t.Errorf("getNamedRepos() unique = %v, want %v", gotUnique, tt.wantUnique)
+			}
+			if !reflect.DeepEqual(gotGroup, tt.wantGroup) {
+				t.Errorf("getNamedRepos() group = %v, want %v", gotGroup, tt.wantGroup)
+			}
+		})
+	}
+}
+
+func Test_getJobs(t *testing.T) {
+	type args struct {
+		jobs      int
+		repoCount int
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{"none", args{4, 0}, 0},
+		{"given", args{4, 10}, 4},
+		{"capped", args{8, 3}, 3},
+		{"single", args{1, 3}, 1},
+		{"default", args{0, 1}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getJobs(tt.args.jobs, tt.args.repoCount); got != tt.want {
+				t.Errorf("getJobs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_countFailed(t *testing.T) {
+	type args struct {
+		repos []tRepo
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{"nil", args{[]tRepo{}}, 0},
+		{"none", args{[]tRepo{{ShortName: "a"}, {ShortName: "b"}}}, 0},
+		{"one", args{[]tRepo{{ShortName: "a"}, {ShortName: "b", Error: "failed"}}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countFailed(tt.args.repos); got != tt.want {
+				t.Errorf("countFailed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_countIncomplete(t *testing.T) {
+	type args struct {
+		repos []tRepo
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{"nil", args{[]tRepo{}}, 0},
+		{"failed", args{[]tRepo{{ShortName: "a", Error: "failed"}}}, 0},
+		{"one", args{[]tRepo{{ShortName: "a"}, {ShortName: "b", Incomplete: true}}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countIncomplete(tt.args.repos); got != tt.want {
+				t.Errorf("countIncomplete() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseLastCommit(t *testing.T) {
+	thisDate, err := time.Parse(time.RFC3339, "2023-11-14T23:13:20+01:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		output string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    tRepo
+		wantErr bool
+	}{
+		{"nil", args{""}, tRepo{}, true},
+		{"short", args{"2 days ago\x002023-11-14T23:13:20+01:00\x00a1b2c3d"}, tRepo{}, true},
+		{"bad date", args{"2 days ago\x001700000000\x00a1b2c3d\x00Łukasz\x00l@example.com\x00Fix"}, tRepo{}, true},
+		{"full", args{"2 days ago\x002023-11-14T23:13:20+01:00\x00a1b2c3d\x00Łukasz\x00l@example.com\x00Fix the x\x00y"},
+			tRepo{LastCommitTime: "2 days ago", LastCommitEpoch: 1700000000, LastCommitDate: &thisDate, LastCommitHash: "a1b2c3d",
+				LastCommitAuthor: "Łukasz", LastCommitEmail: "l@example.com", LastCommitSubject: "Fix the x\x00y"}, false},
+		{"empty subject", args{"2 days ago\x002023-11-14T23:13:20+01:00\x00a1b2c3d\x00Łukasz\x00l@example.com\x00"},
+			tRepo{LastCommitTime: "2 days ago", LastCommitEpoch: 1700000000, LastCommitDate: &thisDate, LastCommitHash: "a1b2c3d",
+				LastCommitAuthor: "Łukasz", LastCommitEmail: "l@example.com"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got tRepo
+			err := parseLastCommit(&got, tt.args.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseLastCommit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLastCommit() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseStashAge(t *testing.T) {
+	thisDate, err := time.Parse(time.RFC3339, "2023-11-14T23:13:20+01:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		output string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    tRepo
+		wantErr bool
+	}{
+		{"nil", args{""}, tRepo{}, false},
+		{"malformed", args{"2 days ago"}, tRepo{}, true},
+		{"single", args{"3 weeks ago\x002023-11-14T23:13:20+01:00"},
+			tRepo{StashAge: "3 weeks ago", StashOldestEpoch: 1700000000, StashOldestDate: &thisDate}, false},
+		{"oldest last", args{"2 days ago\x002024-01-01T10:00:00+01:00\n3 weeks ago\x002023-11-14T23:13:20+01:00"},
+			tRepo{StashAge: "3 weeks ago", StashOldestEpoch: 1700000000, StashOldestDate: &thisDate}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got tRepo
+			err := parseStashAge(&got, tt.args.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseStashAge() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseStashAge() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseLeftRight(t *testing.T) {
+	type args struct {
+		output string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantLeft  int
+		wantRight int
+		wantErr   bool
+	}{
+		{"nil", args{""}, 0, 0, true},
+		{"single", args{"3"}, 0, 0, true},
+		{"nan", args{"3\tx"}, 0, 0, true},
+		{"synced", args{"0\t0"}, 0, 0, false},
+		{"diverged", args{"3\t12"}, 3, 12, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotLeft, gotRight, err := parseLeftRight(tt.args.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseLeftRight() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotLeft != tt.wantLeft || gotRight != tt.wantRight {
+				t.Errorf("parseLeftRight() = %v, %v, want %v, %v", gotLeft, gotRight, tt.wantLeft, tt.wantRight)
+			}
+		})
+	}
+}
+
+func Test_getStatusAB(t *testing.T) {
+	type args struct {
+		ahead  int
+		behind int
 	}
 	tests := []struct {
 		name string
 		args args
 		want string
 	}{
-		{"not", args{`(?mU)^# branch.head (.+)$`, ``}, ""},
-		{"not", args{`(?mU)^# branch.head (.+)$`, `bulba`}, ""},
-		{"nil", args{`(?mU)^# branch.head (.+)$`, `# branch.head`}, ""},
-		{"branch", args{`(?mU)^# branch.head (.+)$`, `# branch.head dudek`}, "dudek"},
-		{"branch", args{`(?mU)^# branch.head (.+)$`, `# branch.head krzysztof dudek`}, "krzysztof dudek"},
+		{"synced", args{0, 0}, SYNCED_CHAR},
+		{"behind", args{0, 2}, REMOTE_AHEAD_CHAR},
+		{"ahead", args{2, 0}, LOCAL_AHEAD_CHAR},
+		{"diverged", args{1, 2}, DIVERGED_CHAR},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getStatusAB(tt.args.ahead, tt.args.behind); got != tt.want {
+				t.Errorf("getStatusAB() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getShownColumns(t *testing.T) {
+	type args struct {
+		thisConfig tConfig
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{"flags", args{tConfig{showBranchHead: true, showDirty: true}}, []string{"name", "branch", "ab", "dirty"}},
+		{"listed", args{tConfig{showBranchHead: true, columns: []string{"dirty", "name", "url"}}}, []string{"dirty", "name", "url"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, thisColumn := range getShownColumns(tt.args.thisConfig) {
+				got = append(got, thisColumn.id)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getShownColumns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_enableColumns(t *testing.T) {
+	type args struct {
+		columns []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    tConfig
+		wantErr bool
+	}{
+		{"nil", args{nil}, tConfig{}, false},
+		{"unknown", args{[]string{"name", "bogus"}}, tConfig{}, true},
+		{"enabled", args{[]string{"branch", "stash-age", "ab"}}, tConfig{showBranchHead: true, showStashAge: true}, false},
+		{"untracked count", args{[]string{"untracked-count"}}, tConfig{showUntracked: true}, false},
+		{"parent", args{[]string{"parent"}}, tConfig{showParent: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tConfig{columns: tt.args.columns}
+			err := enableColumns(&got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("enableColumns() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			tt.want.columns = tt.args.columns
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("enableColumns() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sortRepos(t *testing.T) {
+	thisRepos := []tRepo{
+		{UniqueName: "b", TopLevelGroup: "x", LastCommitEpoch: 900000000},
+		{UniqueName: "a", TopLevelGroup: "y", LastCommitEpoch: 1700000000, Dirty: true},
+		{UniqueName: "c", TopLevelGroup: "x", LastCommitEpoch: 0},
+		{UniqueName: "d", TopLevelGroup: "x", LastCommitEpoch: 1000000000, Dirty: true, Modified: 12},
+		{UniqueName: "e", TopLevelGroup: "y", LastCommitEpoch: 1000000000, Modified: 3},
+	}
+
+	type args struct {
+		keys []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{"name", args{[]string{"name"}}, []string{"a", "b", "c", "d", "e"}, false},
+		{"time numeric", args{[]string{"-time"}}, []string{"a", "d", "e", "b", "c"}, false},
+		{"multiple", args{[]string{"-dirty", "group", "name"}}, []string{"d", "a", "b", "c", "e"}, false},
+		{"counts", args{[]string{"-modified"}}, []string{"d", "e", "b", "a", "c"}, false},
+		{"unknown", args{[]string{"bogus"}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thisSorted := append([]tRepo{}, thisRepos...)
+			err := sortRepos(thisSorted, tt.args.keys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("sortRepos() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			var got []string
+			for _, thisRepo := range thisSorted {
+				got = append(got, thisRepo.UniqueName)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortRepos() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseWhere(t *testing.T) {
+	type args struct {
+		expression string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"bool", args{"dirty"}, false},
+		{"negated", args{"!dirty"}, false},
+		{"count", args{"stashCount > 0"}, false},
+		{"text", args{"branchHead==main"}, false},
+		{"regex", args{"originUrl~github"}, false},
+		{"date", args{"lastCommitDate<2024-01-31"}, false},
+		{"unknown field", args{"bogus==1"}, true},
+		{"no operator", args{"stashCount"}, true},
+		{"unknown operator", args{"stashCount=1"}, true},
+		{"not a number", args{"stashCount>x"}, true},
+		{"not a date", args{"lastCommitDate<yesterday"}, true},
+		{"regex on count", args{"stashCount~1"}, true},
+		{"bad regex", args{"branchHead~("}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseWhere(tt.args.expression); (err != nil) != tt.wantErr {
+				t.Errorf("parseWhere() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_isMatching(t *testing.T) {
+	thisDate, err := time.Parse(time.RFC3339, "2023-11-14T23:13:20+01:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	thisRepo := tRepo{BranchHead: "main", Dirty: true, Ahead: 2, StashCount: 1, LastCommitDate: &thisDate,
+		OriginUrl: "git@github.com:lukasz-lobocki/gitas"}
+
+	type args struct {
+		thisConfig tConfig
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"none", args{tConfig{}}, true},
+		{"dirty", args{tConfig{dirtyOnly: true}}, true},
+		{"ahead", args{tConfig{aheadOnly: true}}, true},
+		{"behind", args{tConfig{behindOnly: true}}, false},
+		{"diverged", args{tConfig{divergedOnly: true}}, false},
+		{"no upstream", args{tConfig{noUpstreamOnly: true}}, true},
+		{"on branch", args{tConfig{onBranch: "main"}}, true},
+		{"on other branch", args{tConfig{onBranch: "dev"}}, false},
+		{"where bool", args{tConfig{where: []string{"!dirty"}}}, false},
+		{"where count", args{tConfig{where: []string{"stashCount>=1", "ahead<3"}}}, true},
+		{"where text", args{tConfig{where: []string{"branchHead!=main"}}}, false},
+		{"where regex", args{tConfig{where: []string{"originUrl~^git@github"}}}, true},
+		{"where date", args{tConfig{where: []string{"lastCommitDate<2024-01-31"}}}, true},
+		{"where time", args{tConfig{where: []string{"lastCommitDate>2023-11-14T23:00:00+01:00"}}}, true},
+		{"where missing date", args{tConfig{where: []string{"stashOldestDate<2024-01-31"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var thisConditions []tCondition
+			for _, thisExpression := range tt.args.thisConfig.where {
+				thisCondition, err := parseWhere(thisExpression)
+				if err != nil {
+					t.Fatal(err)
+				}
+				thisConditions = append(thisConditions, thisCondition)
+			}
+			if got := isMatching(thisRepo, tt.args.thisConfig, thisConditions); got != tt.want {
+				t.Errorf("isMatching() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_hasGitEntry(t *testing.T) {
+	thisRoot := t.TempDir()
+
+	mustMkdir(t, filepath.Join(thisRoot, "plain"))
+	mustMkdir(t, filepath.Join(thisRoot, "repo", ".git"))
+	mustMkdir(t, filepath.Join(thisRoot, "gitfile"))
+	if err := os.WriteFile(filepath.Join(thisRoot, "gitfile", ".git"), []byte("gitdir: ../repo/.git\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		dirName string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"plain", args{filepath.Join(thisRoot, "plain")}, false},
+		{"missing", args{filepath.Join(thisRoot, "missing")}, false},
+		{"dir", args{filepath.Join(thisRoot, "repo")}, true},
+		{"file", args{filepath.Join(thisRoot, "gitfile")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasGitEntry(tt.args.dirName); got != tt.want {
+				t.Errorf("hasGitEntry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Benchmark_findRepos(b *testing.B) {
+	thisRoot := b.TempDir()
+
+	/* Synthetic tree of 10 repos, each next to a plain dir holding 50 chains of 9 nested dirs */
+
+	for i := 0; i < 10; i++ {
+		thisRepo := filepath.Join(thisRoot, fmt.Sprintf("repo%d", i))
+		mustMkdir(b, thisRepo)
+		if out, err := exec.Command("git", "init", "-q", thisRepo).CombinedOutput(); err != nil {
+			b.Fatalf("git init failed. %v: %s", err, out)
+		}
+		for j := 0; j < 50; j++ {
+			mustMkdir(b, filepath.Join(thisRoot, fmt.Sprintf("plain%d", i), fmt.Sprintf("a%d", j), "b", "c", "d", "e", "f", "g", "h", "i"))
+		}
+	}
+
+	loggingLevel = 0
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		thisResult, _, err := findRepos(context.Background(), thisRoot, tConfig{maxDepth: -1})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(thisResult) != 10 {
+			b.Fatalf("findRepos() found %d repos, want 10", len(thisResult))
+		}
+	}
+}
+
+/*
+mustMkdir creates directory with all its parents, failing the test otherwise
+*/
+func mustMkdir(tb testing.TB, dirName string) {
+	tb.Helper()
+	if err := os.MkdirAll(dirName, 0o755); err != nil {
+		tb.Fatal(err)
+	}
+}
+
+func Test_isExcluded(t *testing.T) {
+	thisExcluder := new(tExcluder)
+	for _, thisLine := range []string{"# comment", "", "node_modules/", "go/pkg/mod", "**/.cache", "archive*", "!archive-keep"} {
+		thisExcluder.addLine(thisLine, "")
+	}
+	thisExcluder.addLine("build", "src/app")
+	thisExcluder.addLine("/vendor", "src")
+
+	type args struct {
+		relPath string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"plain", args{"src"}, false},
+		{"unanchored", args{"node_modules"}, true},
+		{"unanchored-deep", args{"src/app/node_modules"}, true},
+		{"anchored", args{"go/pkg/mod"}, true},
+		{"anchored-deep", args{"x/go/pkg/mod"}, false},
+		{"doublestar", args{"a/b/.cache"}, true},
+		{"wildcard", args{"archive-2020"}, true},
+		{"negated", args{"archive-keep"}, false},
+		{"based", args{"src/app/build"}, true},
+		{"based-outside", args{"build"}, false},
+		{"based-anchored", args{"src/vendor"}, true},
+		{"based-anchored-deep", args{"src/app/vendor"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := thisExcluder.isExcluded(tt.args.relPath); got != tt.want {
+				t.Errorf("isExcluded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_walkDirs(t *testing.T) {
+	thisRoot := t.TempDir()
+	thisTarget := t.TempDir()
+
+	mustMkdir(t, filepath.Join(thisRoot, "a", "b", "c"))
+	mustMkdir(t, filepath.Join(thisTarget, "work"))
+	if err := os.Symlink(thisTarget, filepath.Join(thisRoot, "a", "link")); err != nil {
+		t.Skip("symlinks not supported.", err)
+	}
+	if err := os.Symlink(thisRoot, filepath.Join(thisTarget, "loop")); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		config tConfig
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{"unlimited", args{tConfig{maxDepth: -1}}, []string{".", "a", "a/b", "a/b/c"}},
+		{"depth", args{tConfig{maxDepth: 2}}, []string{".", "a", "a/b"}},
+		{"root", args{tConfig{maxDepth: 0}}, []string{"."}},
+		{"symlinks", args{tConfig{maxDepth: -1, followSymlinks: true}}, []string{".", "a", "a/b", "a/b/c", "a/link", "a/link/work"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := walkDirs(thisRoot, tt.args.config, func(thisPath string, thisDir fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if thisPath != "." && thisDir.Name() != filepath.Base(thisPath) {
+					t.Errorf("walkDirs() entry %s named %s", thisPath, thisDir.Name())
+				}
+				if thisDir.IsDir() {
+					got = append(got, thisPath)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("walkDirs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("walkDirs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_findRepos_unreadable(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root reads everything.")
+	}
+
+	thisRoot := t.TempDir()
+	thisLocked := filepath.Join(thisRoot, "locked")
+
+	mustMkdir(t, filepath.Join(thisLocked, "x"))
+	if err := os.Chmod(thisLocked, 0o000); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(thisLocked, 0o755)
+
+	_, gotUnreadable, err := findRepos(context.Background(), thisRoot, tConfig{maxDepth: -1})
+	if err != nil {
+		t.Fatalf("findRepos() error = %v", err)
+	}
+	if want := []string{thisLocked}; !reflect.DeepEqual(gotUnreadable, want) {
+		t.Errorf("findRepos() unreadable = %v, want %v", gotUnreadable, want)
+	}
+}
+
+func Test_groupWorktrees(t *testing.T) {
+	type args struct {
+		repos []tRepo
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{"nil", args{[]tRepo{}}, []string{}},
+		{"grouped", args{[]tRepo{
+			{TopLevelPath: "/wt", MainRepo: "/main"},
+			{TopLevelPath: "/other"},
+			{TopLevelPath: "/main"},
+			{TopLevelPath: "/orphan", MainRepo: "/gone"},
+		}}, []string{"/other", "/main", "/wt", "/orphan"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, thisRepo := range groupWorktrees(tt.args.repos) {
+				got = append(got, thisRepo.TopLevelPath)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupWorktrees() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_findReposCached(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir()) // For darwin's cache and config dirs
+
+	thisRoot := t.TempDir()
+	mustMkdir(t, filepath.Join(thisRoot, "a", ".git"))
+	mustMkdir(t, filepath.Join(thisRoot, "b", ".git"))
+
+	/* Cache is filled with fake repos, as nothing has to be searched */
+
+	thisConfig := tConfig{maxDepth: -1, cacheTTL: time.Hour}
+	thisCacheFile, thisCache, err := getCache(thisRoot, thisConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	thisCache.Repos = []string{filepath.Join(thisRoot, "a"), filepath.Join(thisRoot, "b")}
+	thisCache.Created = time.Now()
+	if err := writeCache(thisCacheFile, thisCache); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		got, _, err := findReposCached(context.Background(), thisRoot, thisConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, thisCache.Repos) {
+			t.Errorf("findReposCached() = %v, want %v", got, thisCache.Repos)
+		}
+	})
+
+	t.Run("other-options", func(t *testing.T) {
+		if _, thisOther, _ := getCache(thisRoot, tConfig{maxDepth: 1}); !thisOther.Created.IsZero() {
+			t.Errorf("getCache() found cache made with other options")
+		}
+	})
+
+	t.Run("user-rules", func(t *testing.T) {
+		thisUserFile := getUserIgnoreFile()
+		mustMkdir(t, filepath.Dir(thisUserFile))
+		if err := os.WriteFile(thisUserFile, []byte("node_modules/\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(thisUserFile)
+		if _, thisOther, _ := getCache(thisRoot, thisConfig); !thisOther.Created.IsZero() {
+			t.Errorf("getCache() found cache made with other user rules")
+		}
+	})
+
+	t.Run("rule-file-edited", func(t *testing.T) {
+		thisRuleFile := filepath.Join(thisRoot, IGNORE_FILE)
+		if err := os.WriteFile(thisRuleFile, []byte("b/\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		thisExcluder := new(tExcluder)
+		if err := thisExcluder.addFile(thisRuleFile, ""); err != nil {
+			t.Fatal(err)
+		}
+		thisEdited := thisCache
+		thisEdited.RuleFiles = thisExcluder.files
+		if !isCacheValid(thisEdited, thisConfig) {
+			t.Errorf("isCacheValid() = false for unchanged rule file")
+		}
+		if err := os.Chtimes(thisRuleFile, time.Now(), time.Now().Add(time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+		if isCacheValid(thisEdited, thisConfig) {
+			t.Errorf("isCacheValid() = true for edited rule file")
+		}
+		if err := os.Remove(thisRuleFile); err != nil {
+			t.Fatal(err)
+		}
+		if isCacheValid(thisEdited, thisConfig) {
+			t.Errorf("isCacheValid() = true for removed rule file")
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		if isCacheValid(thisCache, tConfig{cacheTTL: time.Nanosecond}) {
+			t.Errorf("isCacheValid() = true for expired cache")
+		}
+	})
+
+	t.Run("invalidated", func(t *testing.T) {
+		if err := os.RemoveAll(filepath.Join(thisRoot, "b")); err != nil {
+			t.Fatal(err)
+		}
+		if isCacheValid(thisCache, thisConfig) {
+			t.Errorf("isCacheValid() = true for cache listing removed repo")
+		}
+	})
+}
+
+func Test_parsePorcelainV2(t *testing.T) {
+	tests := []struct {
+		name      string
+		fixture   string
+		want      tPorcelain
+		wantDirty bool
+	}{
+		{"clean", "clean.txt", tPorcelain{
+			branchOid: "7a1a45d9731bbeb351556a6b91659455e9dac7a1", branchHead: "main", branchUpstream: "origin/main",
+			hasAB: true}, false},
+		{"dirty", "dirty.txt", tPorcelain{
+			branchOid: "dab1b7c0c6929c3d310198516b8198ca124f11fe", branchHead: "main", branchUpstream: "origin/main",
+			hasAB: true, ahead: 1, stash: 1,
+			staged: 3, modified: 2, deleted: 2, renamed: 1, untracked: 1}, true},
+		{"conflict", "conflict.txt", tPorcelain{
+			branchOid: "a987cddfcc9db6568052ba097feccd61f25f3ab6", branchHead: "main",
+			unmerged: 1, untracked: 1}, true},
+		{"detached", "detached.txt", tPorcelain{
+			branchOid: "7a1a45d9731bbeb351556a6b91659455e9dac7a1", branchHead: "(detached)"}, false},
+		{"initial", "initial.txt", tPorcelain{
+			branchOid: "(initial)", branchHead: "main"}, false},
+		{"intent to add", "intent.txt", tPorcelain{
+			branchOid: "68a45e7c288b8a6d52308d18f44482bc39e846fb", branchHead: "main",
+			modified: 1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thisBytes, err := os.ReadFile(filepath.Join("testdata", "porcelain", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := parsePorcelainV2(string(thisBytes))
+			if err != nil {
+				t.Fatalf("parsePorcelainV2() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parsePorcelainV2() = %+v, want %+v", got, tt.want)
+			}
+			if got.isDirty() != tt.wantDirty {
+				t.Errorf("isDirty() = %v, want %v", got.isDirty(), tt.wantDirty)
+			}
+		})
+	}
+}
+
+func Test_parseCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		thisCount int
+		want      string
+	}{
+		{"zero", 0, ""},
+		{"one", 1, "1"},
+		{"many", 42, "42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCount(tt.thisCount); got != tt.want {
+				t.Errorf("parseCount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getOperation(t *testing.T) {
+	tests := []struct {
+		name    string
+		present []string
+		want    string
+	}{
+		{"none", []string{"HEAD"}, ""},
+		{"merge", []string{"MERGE_HEAD"}, MERGE_OPERATION},
+		{"rebase-merge", []string{"rebase-merge/"}, REBASE_OPERATION},
+		{"rebase-apply", []string{"rebase-apply/"}, REBASE_OPERATION},
+		{"am", []string{"rebase-apply/", "rebase-apply/applying"}, AM_OPERATION},
+		{"cherry-pick", []string{"CHERRY_PICK_HEAD"}, CHERRY_PICK_OPERATION},
+		{"revert", []string{"REVERT_HEAD"}, REVERT_OPERATION},
+		{"bisect", []string{"BISECT_LOG"}, BISECT_OPERATION},
+		{"rebase-over-bisect", []string{"BISECT_LOG", "rebase-merge/"}, REBASE_OPERATION},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thisGitDir := t.TempDir()
+			for _, thisName := range tt.present {
+				thisPath := filepath.Join(thisGitDir, thisName)
+				if strings.HasSuffix(thisName, "/") {
+					mustMkdir(t, thisPath)
+				} else if err := os.WriteFile(thisPath, []byte{}, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := getOperation(thisGitDir); got != tt.want {
+				t.Errorf("getOperation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getBranchHeadText(t *testing.T) {
+	const thisOid = "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678"
+	tests := []struct {
+		name string
+		tr   tRepo
+		want string
+	}{
+		{"branch", tRepo{BranchHead: "main", CommitOid: thisOid}, "main"},
+		{"detached", tRepo{BranchHead: DETACHED_HEAD, CommitOid: thisOid}, "(a1b2c3d)"},
+		{"tagged", tRepo{BranchHead: DETACHED_HEAD, CommitOid: thisOid, NearestTag: "v1.4.2"}, "v1.4.2 (a1b2c3d)"},
+		{"above-tag", tRepo{BranchHead: DETACHED_HEAD, CommitOid: thisOid, NearestTag: "v1.4.2", TagDistance: 3}, "v1.4.2~3 (a1b2c3d)"},
+		{"no-oid", tRepo{BranchHead: DETACHED_HEAD}, DETACHED_HEAD},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getBranchHeadText(tt.tr); got != tt.want {
+				t.Errorf("getBranchHeadText() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getRemoteOid(t *testing.T) {
+	const thisOutput = "1111111111111111111111111111111111111111\trefs/heads/main\n" +
+		"2222222222222222222222222222222222222222\trefs/heads/feature/main"
+	tests := []struct {
+		name string
+		ref  string
+		want string
+	}{
+		{"exact", "refs/heads/main", "1111111111111111111111111111111111111111"},
+		{"slashes", "refs/heads/feature/main", "2222222222222222222222222222222222222222"},
+		{"gone", "refs/heads/gone", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getRemoteOid(thisOutput, tt.ref); got != tt.want {
+				t.Errorf("getRemoteOid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_runCommand_timeout(t *testing.T) {
+	type args struct {
+		config tConfig
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{"timed out", args{tConfig{gitTimeout: time.Nanosecond}}, errTimeout},
+		{"unlimited", args{tConfig{}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := runCommand(context.Background(), tt.args.config, "git", []string{"--version"}, "."); !errors.Is(err, tt.wantErr) {
+				t.Errorf("runCommand() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_queryRepos(t *testing.T) {
+	thisRoot := t.TempDir()
+	for i := 0; i < 3; i++ {
+		thisRepo := filepath.Join(thisRoot, fmt.Sprintf("repo%d", i))
+		mustMkdir(t, thisRepo)
+		if out, err := exec.Command("git", "init", "-q", thisRepo).CombinedOutput(); err != nil {
+			t.Fatalf("git init failed. %v: %s", err, out)
+		}
+	}
+
+	loggingLevel = 0
+
+	thisCancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	thisExpired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	thisConfig := tConfig{jobs: 2, dateKind: &tChoice{Value: "c"}, timeFormat: &tChoice{Value: "i"}, showCommitTime: true}
+
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name           string
+		args           args
+		wantIncomplete bool
+		wantTimedOut   bool
+	}{
+		{"no commits", args{context.Background()}, false, false},
+		{"interrupted", args{thisCancelled}, true, false},
+		{"deadline", args{thisExpired}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thisRepos := getNamedRepos([]string{
+				filepath.Join(thisRoot, "repo0"),
+				filepath.Join(thisRoot, "repo1"),
+				filepath.Join(thisRoot, "repo2")})
+			queryRepos(tt.args.ctx, thisRepos, thisConfig)
+
+			/* Every repo is still reported, either partial or failing */
+
+			if len(thisRepos) != 3 {
+				t.Fatalf("queryRepos() kept %d repos, want 3", len(thisRepos))
+			}
+			for _, thisRepo := range thisRepos {
+				if thisRepo.Incomplete != tt.wantIncomplete || thisRepo.TimedOut != tt.wantTimedOut || (thisRepo.Error != "") != tt.wantTimedOut {
+					t.Errorf("queryRepos() %s: incomplete %v, timed out %v, error %q", thisRepo.ShortName, thisRepo.Incomplete, thisRepo.TimedOut, thisRepo.Error)
+				}
+			}
+		})
+	}
+}
+
+func Test_isRunOver(t *testing.T) {
+	thisCancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	type args struct {
+		ctx context.Context
+		err error
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"failed", args{context.Background(), errors.New("exit status 128")}, false},
+		{"timed out", args{context.Background(), fmt.Errorf("running git failed. %w", errTimeout)}, true},
+		{"cancelled", args{thisCancelled, fmt.Errorf("running git failed. %w", context.Canceled)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRunOver(tt.args.ctx, tt.args.err); got != tt.want {
+				t.Errorf("isRunOver() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getNearestTag_cancelled(t *testing.T) {
+	thisRepo := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", thisRepo).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed. %v: %s", err, out)
+	}
+
+	thisCancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := getNearestTag(context.Background(), &tRepo{TopLevelPath: thisRepo}, tConfig{}); err != nil {
+		t.Errorf("getNearestTag() error = %v, want nil", err)
+	}
+	if err := getNearestTag(thisCancelled, &tRepo{TopLevelPath: thisRepo}, tConfig{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("getNearestTag() error = %v, want %v", err, context.Canceled)
+	}
+	if err := getBareStatus(thisCancelled, &tRepo{TopLevelPath: thisRepo}, tConfig{showBranchHead: true}); !errors.Is(err, context.Canceled) {
+		t.Errorf("getBareStatus() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func Test_enableFilters(t *testing.T) {
+	type args struct {
+		config tConfig
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    tConfig
+		wantErr bool
+	}{
+		{"none", args{tConfig{}}, tConfig{}, false},
+		{"on branch", args{tConfig{onBranch: "main"}}, tConfig{onBranch: "main", showBranchHead: true}, false},
+		{"untracked count", args{tConfig{where: []string{"untrackedCount>0"}}}, tConfig{where: []string{"untrackedCount>0"}, showUntracked: true}, false},
+		{"parent", args{tConfig{where: []string{"parent~lib"}}}, tConfig{where: []string{"parent~lib"}, showParent: true}, false},
+		{"invalid", args{tConfig{where: []string{"==1"}}}, tConfig{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.args.config
+			_, err := enableFilters(&got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("enableFilters() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("enableFilters() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseRepoLayout(t *testing.T) {
+	type args struct {
+		output string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    tRepo
+		wantErr bool
+	}{
+		{"plain", args{".git\n.git\nfalse"}, tRepo{TopLevelPath: "/src/a", GitDir: "/src/a/.git"}, false},
+		{"bare", args{".\n.\ntrue"}, tRepo{TopLevelPath: "/src/a", GitDir: "/src/a", Bare: true}, false},
+		{"linked", args{"/src/main/.git/worktrees/a\n/src/main/.git\nfalse"},
+			tRepo{TopLevelPath: "/src/a", GitDir: "/src/main/.git/worktrees/a", MainRepo: "/src/main"}, false},
+		{"linked relative", args{"/src/main/.git/worktrees/a\n../main/.git\nfalse"},
+			tRepo{TopLevelPath: "/src/a", GitDir: "/src/main/.git/worktrees/a", MainRepo: "/src/main"}, false},
+		{"linked to bare", args{"/src/up.git/worktrees/a\n/src/up.git\nfalse"},
+			tRepo{TopLevelPath: "/src/a", GitDir: "/src/up.git/worktrees/a", MainRepo: "/src/up.git"}, false},
+		{"malformed", args{".git"}, tRepo{TopLevelPath: "/src/a"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tRepo{TopLevelPath: "/src/a"}
+			err := parseRepoLayout(&got, tt.args.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseRepoLayout() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRepoLayout() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_readRepoList(t *testing.T) {
+	thisList := filepath.Join(t.TempDir(), "repos.txt")
+	if err := os.WriteFile(thisList, []byte("# work\n/src/a\n\n  /src/b  \n\t\n# /src/c\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		fileName string
+		stdin    string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{"file", args{thisList, ""}, []string{"/src/a", "/src/b"}, false},
+		{"stdin", args{"-", "/src/a\n\n# skipped\n/src/d\n"}, []string{"/src/a", "/src/d"}, false},
+		{"empty", args{"-", "\n# nothing\n"}, nil, false},
+		{"missing", args{filepath.Join(t.TempDir(), "missing.txt"), ""}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.args.fileName == "-" {
+				thisStdin := filepath.Join(t.TempDir(), "stdin")
+				if err := os.WriteFile(thisStdin, []byte(tt.args.stdin), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				thisFile, err := os.Open(thisStdin)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer thisFile.Close()
+				thisSaved := os.Stdin
+				defer func() { os.Stdin = thisSaved }()
+				os.Stdin = thisFile
+			}
+			got, err := readRepoList(tt.args.fileName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readRepoList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readRepoList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getGitsSlice(t *testing.T) {
+	thisRoot, err := filepath.EvalSymlinks(t.TempDir()) // Git reports real paths
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, thisName := range []string{"a", "b"} {
+		if out, err := exec.Command("git", "init", "-q", filepath.Join(thisRoot, "src", thisName)).CombinedOutput(); err != nil {
+			t.Fatalf("git init failed. %v: %s", err, out)
+		}
+	}
+	mustMkdir(t, filepath.Join(thisRoot, "plain"))
+	mustMkdir(t, filepath.Join(thisRoot, "src", "a", "deep"))
+
+	thisList := filepath.Join(thisRoot, "repos.txt")
+	if err := os.WriteFile(thisList, []byte("# listed\n"+filepath.Join(thisRoot, "src", "a", "deep")+"\n\n"+filepath.Join(thisRoot, "plain")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loggingLevel = 0
+
+	var (
+		thisA = filepath.Join(thisRoot, "src", "a")
+		thisB = filepath.Join(thisRoot, "src", "b")
+	)
+
+	type args struct {
+		dirNames []string
+		fromFile string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{"single", args{[]string{thisA}, ""}, []string{thisA}},
+		{"duplicate roots", args{[]string{thisA, thisA, filepath.Join(thisRoot, "src")}, ""}, []string{thisA, thisB}},
+		{"listed only", args{nil, thisList}, []string{thisA}},
+		{"listed and found", args{[]string{thisB, thisA}, thisList}, []string{thisB, thisA}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := getGitsSlice(context.Background(), tt.args.dirNames, tConfig{maxDepth: -1, fromFile: tt.args.fromFile})
+			if err != nil {
+				t.Fatalf("getGitsSlice() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getGitsSlice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getArgsStatus(t *testing.T) {
+	type args struct {
+		config tConfig
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{"default", args{tConfig{}},
+			[]string{"status", "--branch", "--porcelain=2", "--untracked-files=no", "--ignore-submodules=all"}},
+		{"nested", args{tConfig{lookForSubGits: true}},
+			[]string{"status", "--branch", "--porcelain=2", "--untracked-files=no"}},
+		{"submodules", args{tConfig{lookForSubGits: true, showSubmodules: true}},
+			[]string{"status", "--branch", "--porcelain=2", "--untracked-files=no"}},
+		{"stash and untracked", args{tConfig{showStash: true, showUntracked: true}},
+			[]string{"status", "--branch", "--porcelain=2", "--show-stash", "--ignore-submodules=all"}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := getStringRegex(tt.args.expression, tt.args.input); got != tt.want {
-				t.Errorf("getStringRegex() = %v, want %v", got, tt.want)
+			if got := getArgsStatus(tt.args.config); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getArgsStatus() = %v, want %v", got, tt.want)
 			}
 		})
 	}